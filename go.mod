@@ -3,6 +3,7 @@ module github.com/transientvariable/config-go
 go 1.24.1
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/dustin/go-humanize v1.0.1
 	github.com/multiformats/go-multiaddr v0.16.0
 	github.com/stretchr/testify v1.10.0
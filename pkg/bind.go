@@ -0,0 +1,265 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// The struct tag used for mapping struct fields to configuration paths.
+	bindTag = `config`
+
+	// The struct tag option used for parsing a field value as a byte size (e.g. `5MiB`).
+	bindTagOptionSize = `size`
+)
+
+// Bind populates the struct, slice, map, or scalar referenced by the provided target pointer using the configuration
+// values for the provided path, or the root Path of the Config if the provided path is empty.
+//
+// Struct fields are mapped to sub-paths using the `config` struct tag, for example `config:"timeout"`, or the field
+// name if no tag is provided. Field names are matched ignoring case. Fields with the tag `config:"-"` are skipped, and
+// fields whose path is not present in the configuration retain their current value. Integer fields tagged with the
+// `size` option, for example `config:"limit,size"`, are parsed as byte sizes (e.g. `5MiB`).
+//
+// Slices are populated from collections (e.g. YAML sequences), and maps with string keys are populated from the
+// sub-paths for the field path. All types with a decoder registered using RegisterDecoder (e.g. time.Duration,
+// *url.URL, multiaddr.Multiaddr) are supported, as well as types implementing encoding.TextUnmarshaler.
+//
+// The returned error will be non-nil if:
+//   - the provided target is not a non-nil pointer
+//   - the value corresponding to the provided path could not be found
+//   - a value could not be parsed as the type of the corresponding target field
+func (c *Config) Bind(path string, target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return &PathError{Err: errors.New("target must be a non-nil pointer"), Operation: "bind", Path: path}
+	}
+
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	key := c.root
+	if p := Path(path); !p.Empty() {
+		var ok bool
+		if key, ok = c.lookup(p); !ok {
+			return &PathError{Err: ErrPathNotFound, Operation: "bind", Path: path}
+		}
+	}
+	return c.bind(key, v.Elem(), false)
+}
+
+// bind populates the provided value using the configuration values for the provided key.
+func (c *Config) bind(key Path, v reflect.Value, size bool) error {
+	if ok, err := c.bindScalar(key, v, size); ok {
+		return err
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		e := reflect.New(v.Type().Elem())
+		if !v.IsNil() {
+			e.Elem().Set(v.Elem())
+		}
+
+		if err := c.bind(key, e.Elem(), size); err != nil {
+			return err
+		}
+		v.Set(e)
+		return nil
+	case reflect.Struct:
+		return c.bindStruct(key, v)
+	case reflect.Slice:
+		return c.bindSlice(key, v, size)
+	case reflect.Map:
+		return c.bindMap(key, v, size)
+	default:
+		return &PathError{Err: fmt.Errorf("unsupported type %s", v.Type()), Operation: "bind", Path: key.String()}
+	}
+}
+
+// bindScalar populates the provided value if its type represents a scalar value.
+//
+// Returns true if the type of the provided value represents a scalar value, along with any error that occurred while
+// parsing the configuration value.
+func (c *Config) bindScalar(key Path, v reflect.Value, size bool) (bool, error) {
+	s := c.mapping[key]
+	t := v.Type()
+
+	var err error
+	switch k := t.Kind(); {
+	case size && (isInt(k) || isUint(k)):
+		var i int64
+		if i, err = parseSizeBytes(s); err == nil {
+			err = setInt(v, i)
+		}
+	case decodable(t):
+		var d any
+		if d, err = decode(t, s); err == nil && d != nil {
+			v.Set(reflect.ValueOf(d))
+		}
+	case isInt(k):
+		var i int64
+		if s != "" {
+			i, err = strconv.ParseInt(s, 10, t.Bits())
+		}
+
+		if err == nil {
+			v.SetInt(i)
+		}
+	case isUint(k):
+		var u uint64
+		if s != "" {
+			u, err = strconv.ParseUint(s, 10, t.Bits())
+		}
+
+		if err == nil {
+			v.SetUint(u)
+		}
+	case k == reflect.Bool:
+		var b bool
+		if b, err = strconv.ParseBool(s); err == nil {
+			v.SetBool(b)
+		}
+	case k == reflect.Float32 || k == reflect.Float64:
+		var f float64
+		if s != "" {
+			f, err = strconv.ParseFloat(s, t.Bits())
+		}
+
+		if err == nil {
+			v.SetFloat(f)
+		}
+	case k == reflect.String:
+		v.SetString(s)
+	default:
+		return false, nil
+	}
+
+	if err != nil {
+		return true, &PathError{Err: err, Operation: "bind", Path: key.String()}
+	}
+	return true, nil
+}
+
+// bindStruct populates the exported fields of the provided struct value using the sub-paths for the provided key.
+func (c *Config) bindStruct(key Path, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup(bindTag)
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && !ok && field.Type.Kind() == reflect.Struct {
+			if err := c.bindStruct(key, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if name = strings.TrimSpace(name); name == "" {
+			name = field.Name
+		}
+
+		fieldKey, ok := c.lookup(key.Join(Path(name)))
+		if !ok {
+			continue
+		}
+
+		if err := c.bind(fieldKey, v.Field(i), hasTagOption(options, bindTagOptionSize)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bindSlice populates the provided slice value using the elements of the collection for the provided key.
+func (c *Config) bindSlice(key Path, v reflect.Value, size bool) error {
+	keys, err := c.elements(key)
+	if err != nil {
+		return &PathError{Err: err, Operation: "bind", Path: key.String()}
+	}
+
+	s := reflect.MakeSlice(v.Type(), len(keys), len(keys))
+	for i, elementKey := range keys {
+		if err := c.bind(elementKey, s.Index(i), size); err != nil {
+			return err
+		}
+	}
+	v.Set(s)
+	return nil
+}
+
+// bindMap populates the provided map value using the sub-paths for the provided key.
+func (c *Config) bindMap(key Path, v reflect.Value, size bool) error {
+	t := v.Type()
+	if t.Key().Kind() != reflect.String {
+		return &PathError{Err: fmt.Errorf("unsupported map key type %s", t.Key()), Operation: "bind", Path: key.String()}
+	}
+
+	m := reflect.MakeMap(t)
+	for _, child := range c.children(key, false) {
+		e := reflect.New(t.Elem()).Elem()
+		if err := c.bind(child, e, size); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(child.Base()).Convert(t.Key()), e)
+	}
+	v.Set(m)
+	return nil
+}
+
+func isInt(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Int64
+}
+
+func isUint(k reflect.Kind) bool {
+	return k >= reflect.Uint && k <= reflect.Uint64
+}
+
+// setInt sets the provided signed or unsigned integer value, returning a non-nil error if the integer would overflow.
+func setInt(v reflect.Value, i int64) error {
+	if isUint(v.Kind()) {
+		if i < 0 || v.OverflowUint(uint64(i)) {
+			return fmt.Errorf("value %d overflows type %s", i, v.Type())
+		}
+		v.SetUint(uint64(i))
+		return nil
+	}
+
+	if v.OverflowInt(i) {
+		return fmt.Errorf("value %d overflows type %s", i, v.Type())
+	}
+	v.SetInt(i)
+	return nil
+}
+
+func hasTagOption(options string, option string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if strings.TrimSpace(o) == option {
+			return true
+		}
+	}
+	return false
+}
+
+// Bind populates the struct, slice, map, or scalar referenced by the provided target pointer using the default
+// configuration values for the provided path.
+//
+// See Config.Bind for the supported types and struct tags.
+func Bind(path string, target any) error {
+	c, err := instance()
+	if err != nil {
+		return err
+	}
+	return c.Bind(path, target)
+}
@@ -0,0 +1,470 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// GetAllFrom retrieves the collection of values (e.g. YAML sequence) for the provided path from the provided Config,
+// with each element parsed as type T.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but does not represent a collection
+//   - contains an element that could not be parsed as type T, in which case the PathError records the path of the
+//     element (e.g. `config.hosts.#2`)
+func GetAllFrom[T any](c *Config, path string) ([]T, error) {
+	t := reflect.TypeFor[T]()
+	return decodeAll(c, path, func(v string) (T, error) {
+		var z T
+		d, err := decode(t, v)
+		if err != nil {
+			return z, err
+		}
+
+		// a decoder for an interface type may return a nil value, which is returned as the zero value of T
+		z, _ = d.(T)
+		return z, nil
+	})
+}
+
+// MustGetAllFrom is similar behavior to GetAllFrom, but panics if an error occurs.
+func MustGetAllFrom[T any](c *Config, path string) []T {
+	v, err := GetAllFrom[T](c, path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// GetAll retrieves the collection of values (e.g. YAML sequence) for the provided path from the default
+// configuration, with each element parsed as type T.
+//
+// See GetAllFrom for how values are parsed.
+func GetAll[T any](path string) ([]T, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return GetAllFrom[T](c, path)
+}
+
+// MustGetAll is similar behavior to GetAll, but panics if an error occurs.
+func MustGetAll[T any](path string) []T {
+	v, err := GetAll[T](path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Elements returns a scoped Config for each element of the collection (e.g. YAML sequence) for the provided path, in
+// the order the elements were declared. Values for each element are retrieved using paths relative to the element,
+// for example `host` for the element path `config.servers.#0.host`, and the value of a scalar element (e.g. an
+// element of `[1s, 5s]`) is retrieved using the empty path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found or does not
+// represent a collection.
+func (c *Config) Elements(path string) ([]*Config, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	keys, err := c.elements(Path(path))
+	if err != nil {
+		return nil, err
+	}
+
+	elements := make([]*Config, len(keys))
+	for i, key := range keys {
+		elements[i] = &Config{store: c.store, root: key}
+	}
+	return elements, nil
+}
+
+// Each calls the provided function with the index and scoped Config for each element of the collection (e.g. YAML
+// sequence) for the provided path, in the order the elements were declared. Iteration stops at the first non-nil
+// error returned by the function.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or the provided function returned a non-nil error.
+func (c *Config) Each(path string, fn func(int, *Config) error) error {
+	elements, err := c.Elements(path)
+	if err != nil {
+		return err
+	}
+
+	for i, e := range elements {
+		if err := fn(i, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Elements returns a scoped Config for each element of the collection (e.g. YAML sequence) for the provided path in
+// the default configuration.
+//
+// See Config.Elements for how element values are retrieved.
+func Elements(path string) ([]*Config, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Elements(path)
+}
+
+// Each calls the provided function with the index and scoped Config for each element of the collection (e.g. YAML
+// sequence) for the provided path in the default configuration.
+//
+// See Config.Each for how iteration is performed.
+func Each(path string, fn func(int, *Config) error) error {
+	c, err := instance()
+	if err != nil {
+		return err
+	}
+	return c.Each(path, fn)
+}
+
+// decodeAll parses each element of the collection for the provided path using the provided decoder.
+func decodeAll[T any](c *Config, path string, decoder func(string) (T, error)) ([]T, error) {
+	c.mutex.RLock()
+	keys, err := c.elements(Path(path))
+	if err != nil {
+		c.mutex.RUnlock()
+		return nil, err
+	}
+
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = c.mapping[key]
+	}
+	c.mutex.RUnlock()
+
+	result := make([]T, len(values))
+	for i, v := range values {
+		d, err := decoder(v)
+		if err != nil {
+			return nil, &PathError{Err: err, Operation: "decode", Path: keys[i].String()}
+		}
+		result[i] = d
+	}
+	return result, nil
+}
+
+// Strings retrieves the collection of string values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Strings(path string) ([]string, error) {
+	return GetAllFrom[string](c, path)
+}
+
+// StringsMustResolve is similar behavior to Strings, but panics if an error occurs.
+func (c *Config) StringsMustResolve(path string) []string {
+	v, err := c.Strings(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Strings retrieves the collection of string values for the provided path from the default configuration.
+func Strings(path string) ([]string, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Strings(path)
+}
+
+// StringsMustResolve is similar behavior to Strings, but panics if an error occurs.
+func StringsMustResolve(path string) []string {
+	v, err := Strings(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Bools retrieves the collection of boolean values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Bools(path string) ([]bool, error) {
+	return GetAllFrom[bool](c, path)
+}
+
+// BoolsMustResolve is similar behavior to Bools, but panics if an error occurs.
+func (c *Config) BoolsMustResolve(path string) []bool {
+	v, err := c.Bools(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Bools retrieves the collection of boolean values for the provided path from the default configuration.
+func Bools(path string) ([]bool, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Bools(path)
+}
+
+// BoolsMustResolve is similar behavior to Bools, but panics if an error occurs.
+func BoolsMustResolve(path string) []bool {
+	v, err := Bools(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Durations retrieves the collection of time.Duration values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Durations(path string) ([]time.Duration, error) {
+	return GetAllFrom[time.Duration](c, path)
+}
+
+// DurationsMustResolve is similar behavior to Durations, but panics if an error occurs.
+func (c *Config) DurationsMustResolve(path string) []time.Duration {
+	v, err := c.Durations(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Durations retrieves the collection of time.Duration values for the provided path from the default configuration.
+func Durations(path string) ([]time.Duration, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Durations(path)
+}
+
+// DurationsMustResolve is similar behavior to Durations, but panics if an error occurs.
+func DurationsMustResolve(path string) []time.Duration {
+	v, err := Durations(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Floats retrieves the collection of float values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Floats(path string) ([]float64, error) {
+	return GetAllFrom[float64](c, path)
+}
+
+// FloatsMustResolve is similar behavior to Floats, but panics if an error occurs.
+func (c *Config) FloatsMustResolve(path string) []float64 {
+	v, err := c.Floats(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Floats retrieves the collection of float values for the provided path from the default configuration.
+func Floats(path string) ([]float64, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Floats(path)
+}
+
+// FloatsMustResolve is similar behavior to Floats, but panics if an error occurs.
+func FloatsMustResolve(path string) []float64 {
+	v, err := Floats(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Ints retrieves the collection of integer values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Ints(path string) ([]int, error) {
+	return GetAllFrom[int](c, path)
+}
+
+// IntsMustResolve is similar behavior to Ints, but panics if an error occurs.
+func (c *Config) IntsMustResolve(path string) []int {
+	v, err := c.Ints(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Ints retrieves the collection of integer values for the provided path from the default configuration.
+func Ints(path string) ([]int, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Ints(path)
+}
+
+// IntsMustResolve is similar behavior to Ints, but panics if an error occurs.
+func IntsMustResolve(path string) []int {
+	v, err := Ints(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Multiaddrs retrieves the collection of multiaddr.Multiaddr values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Multiaddrs(path string) ([]multiaddr.Multiaddr, error) {
+	return GetAllFrom[multiaddr.Multiaddr](c, path)
+}
+
+// MultiaddrsMustResolve is similar behavior to Multiaddrs, but panics if an error occurs.
+func (c *Config) MultiaddrsMustResolve(path string) []multiaddr.Multiaddr {
+	v, err := c.Multiaddrs(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Multiaddrs retrieves the collection of multiaddr.Multiaddr values for the provided path from the default configuration.
+func Multiaddrs(path string) ([]multiaddr.Multiaddr, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Multiaddrs(path)
+}
+
+// MultiaddrsMustResolve is similar behavior to Multiaddrs, but panics if an error occurs.
+func MultiaddrsMustResolve(path string) []multiaddr.Multiaddr {
+	v, err := Multiaddrs(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// SizeBytesList retrieves the collection of values representing a unit value of bytes for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) SizeBytesList(path string) ([]int64, error) {
+	return decodeAll(c, path, parseSizeBytes)
+}
+
+// SizeBytesListMustResolve is similar behavior to SizeBytesList, but panics if an error occurs.
+func (c *Config) SizeBytesListMustResolve(path string) []int64 {
+	v, err := c.SizeBytesList(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// SizeBytesList retrieves the collection of values representing a unit value of bytes for the provided path from the default configuration.
+func SizeBytesList(path string) ([]int64, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.SizeBytesList(path)
+}
+
+// SizeBytesListMustResolve is similar behavior to SizeBytesList, but panics if an error occurs.
+func SizeBytesListMustResolve(path string) []int64 {
+	v, err := SizeBytesList(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Times retrieves the collection of time.Time values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) Times(path string) ([]time.Time, error) {
+	return GetAllFrom[time.Time](c, path)
+}
+
+// TimesMustResolve is similar behavior to Times, but panics if an error occurs.
+func (c *Config) TimesMustResolve(path string) []time.Time {
+	v, err := c.Times(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Times retrieves the collection of time.Time values for the provided path from the default configuration.
+func Times(path string) ([]time.Time, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Times(path)
+}
+
+// TimesMustResolve is similar behavior to Times, but panics if an error occurs.
+func TimesMustResolve(path string) []time.Time {
+	v, err := Times(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// URLs retrieves the collection of url.URL values for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found, does not
+// represent a collection, or contains an element that could not be parsed.
+func (c *Config) URLs(path string) ([]*url.URL, error) {
+	return GetAllFrom[*url.URL](c, path)
+}
+
+// URLsMustResolve is similar behavior to URLs, but panics if an error occurs.
+func (c *Config) URLsMustResolve(path string) []*url.URL {
+	v, err := c.URLs(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// URLs retrieves the collection of url.URL values for the provided path from the default configuration.
+func URLs(path string) ([]*url.URL, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.URLs(path)
+}
+
+// URLsMustResolve is similar behavior to URLs, but panics if an error occurs.
+func URLsMustResolve(path string) []*url.URL {
+	v, err := URLs(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -5,22 +5,23 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/transientvariable/anchor"
-
-	"gopkg.in/yaml.v3"
 )
 
 const (
 	// The default file path to load when creating a new default configuration.
 	defaultFilePath = `application.yaml`
 
-	defaultRoot = `config`
+	// The environment variable used for setting the comma-separated list of active profiles.
+	profilesEnvVar = `CONFIG_PROFILES`
 
-	// Format string for matching path prefixes to their corresponding slice elements.
-	formatSlicePrefix = `%s\\.#\\d+`
+	defaultRoot = `config`
 
 	// Template string for matching placeholder values.
 	placeholderTemplate = `$value`
@@ -39,101 +40,309 @@ const (
 )
 
 var (
-	config  *configuration
-	loadErr error
-	once    sync.Once
+	config     atomic.Pointer[Config]
+	configLoad sync.Mutex
 )
 
-type mapConfigFunc func([]byte) (map[string]any, error)
+// Config is a view of the configuration mapping rooted at a Path. Scoped views of a Config (e.g. Elements) share
+// the configuration mapping of the Config they were created from.
+type Config struct {
+	*store
+	root Path
+}
 
-// config is a container for the configuration mapping.
-type configuration struct {
-	filePath string
-	mapping  configMap
-	mutex    sync.RWMutex
-	root     Path
+// store is a container for the configuration mapping.
+type store struct {
+	filePaths []string
+	mapping   configMap
+	mutex     sync.RWMutex
+	namespace Path
+	options   *Option
+	report    LoadReport
+
+	// reloadMutex serializes reloads, so that the mappings read by concurrent reloads are applied, and subscribers are
+	// notified, in the order the reloads were read.
+	reloadMutex sync.Mutex
+
+	subscriptions     map[int]*subscription
+	subscriptionID    int
+	subscriptionMutex sync.Mutex
 }
 
-// Load reads and parses the configuration using the provided optional properties.
+// New creates a new Config by reading and parsing the configuration using the provided optional properties.
 //
 // If an error occurs during read/parse operations, error will be non-nil.
+func New(options ...func(*Option)) (*Config, error) {
+	opts := &Option{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	s := &store{
+		namespace: opts.namespace(),
+		options:   opts,
+	}
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return &Config{store: s, root: s.namespace}, nil
+}
+
+// Load reads and parses the default configuration using the provided optional properties.
+//
+// Once the default configuration has been loaded successfully, subsequent calls to Load without options have no
+// effect, and subsequent calls to Load with options return ErrAlreadyLoaded, since the options would otherwise be
+// ignored. Use Reload to re-read the default configuration, or New to create a configuration using different options.
+// If an error occurs during read/parse operations, error will be non-nil and Load may be called again.
 func Load(options ...func(*Option)) error {
-	once.Do(func() {
-		opts := &Option{}
-		for _, opt := range options {
-			opt(opts)
+	configLoad.Lock()
+	defer configLoad.Unlock()
+
+	if config.Load() != nil {
+		if len(options) > 0 {
+			return ErrAlreadyLoaded
 		}
+		return nil
+	}
+
+	c, err := New(options...)
+	if err != nil {
+		return err
+	}
+	config.Store(c)
+	return nil
+}
+
+// Reload re-reads and parses the configuration using the optional properties provided when the Config was created,
+// re-interpolating any placeholder values. The new configuration mapping atomically replaces the current mapping,
+// including any values that were changed using Set, and subscribers registered using Watch are notified of the
+// resulting changes. Concurrent calls to Reload (e.g. from WatchFile) are serialized, so subscribers must not call
+// Reload synchronously.
+//
+// If an error occurs during read/parse operations, error will be non-nil and the current mapping is retained.
+func (c *Config) Reload() error {
+	return c.store.reload()
+}
+
+// profiles returns the active profiles, using the profiles Option or, if not provided, the `CONFIG_PROFILES`
+// environment variable.
+func (s *store) profiles() []string {
+	if len(s.options.profiles) > 0 {
+		return s.options.profiles
+	}
+	return splitProfiles(os.Getenv(profilesEnvVar))
+}
 
-		filePath := opts.filePath
-		if filePath == "" {
-			filePath = defaultFilePath
+// reload re-reads and parses the configuration, and replaces the current configuration mapping. Concurrent reloads
+// are serialized.
+func (s *store) reload() error {
+	s.reloadMutex.Lock()
+	defer s.reloadMutex.Unlock()
+
+	profiles := s.profiles()
+	layers, err := s.options.layers(profiles)
+	if err != nil {
+		return err
+	}
+
+	if !s.options.provided {
+		// the default file is merged above any default values, and below any overrides
+		i := slices.IndexFunc(layers, func(l layer) bool {
+			_, ok := l.source.(overlay)
+			return ok
+		})
+		if i == -1 {
+			i = len(layers)
 		}
+		layers = slices.Insert(layers, i, fileLayers([]configFile{{path: defaultFilePath}}, profiles)...)
+	}
 
-		rawConfig, err := readConfig(filePath)
-		if err != nil {
-			if !errors.Is(err, os.ErrNotExist) {
-				loadErr = err
-				return
+	mapping, report, err := s.read(append(s.defaults(), layers...))
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	diff := newDiff(s.mapping, mapping)
+	s.filePaths = layerFiles(layers)
+	s.mapping = mapping
+	s.report = report
+	s.mutex.Unlock()
+
+	s.notify(diff)
+	return nil
+}
+
+// read reads, parses, and merges the configuration mapping for the provided sources in order of increasing
+// precedence. Sources that override the values for the paths defined by the sources before them (e.g. environment
+// variable and command-line overrides) are applied to the merged mapping in their position. Placeholder values are
+// interpolated for each source that is not literal, and optional sources that do not exist are skipped.
+//
+// The returned error will be a *LoadError if any required source could not be read.
+func (s *store) read(layers []layer) (configMap, LoadReport, error) {
+	var report LoadReport
+	mapping := make(configMap)
+	placeholder := regexp.MustCompile(placeholderPattern)
+	for _, l := range layers {
+		r := SourceReport{Name: l.source.Name(), Required: l.required, Status: SourceFound}
+		if o, ok := l.source.(overlay); ok {
+			if err := o.override(mapping); err != nil {
+				r.Status = SourceFailed
+				r.Err = err
 			}
+			report.Sources = append(report.Sources, r)
+			continue
 		}
 
-		mapping, err := newConfigMap(rawConfig)
-		if err != nil {
-			loadErr = err
-			return
+		rawConfig, err := l.source.Read()
+		if err == nil {
+			var m configMap
+			if m, err = newConfigMap(rawConfig); err == nil {
+				if !l.literal {
+					for p, v := range m {
+						m[p] = interpolate(placeholder, placeholderTemplate, v)
+					}
+				}
+				mapping.merge(m, emptyMaps(rawConfig))
+			}
 		}
 
-		config = &configuration{
-			filePath: filePath,
-			mapping:  mapping,
-			root:     defaultRoot,
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) && !l.required {
+				r.Status = SourceSkipped
+			} else {
+				r.Status = SourceFailed
+				r.Err = err
+			}
 		}
+		report.Sources = append(report.Sources, r)
+	}
 
-		for p, v := range config.mapping {
-			config.mapping[p] = interpolate(regexp.MustCompile(placeholderPattern), placeholderTemplate, v)
-		}
+	if report.Failed() {
+		return nil, report, &LoadError{Report: report}
+	}
 
-		r := config.root.String()
-		for _, key := range config.mapping.keys() {
-			p := strings.Split(key.String(), ".")
-			if s := strings.TrimSpace(p[0]); s != "" {
-				if !strings.EqualFold(s, r) {
-					loadErr = fmt.Errorf("configuration: multiple root paths defined: %s", s)
-					break
-				}
+	if s.namespace.Empty() {
+		return mapping, report, nil
+	}
+
+	r := s.namespace.String()
+	for _, key := range mapping.keys() {
+		p := strings.Split(key.String(), ".")
+		if k := strings.TrimSpace(p[0]); k != "" {
+			if !strings.EqualFold(k, r) {
+				return nil, report, fmt.Errorf("configuration: multiple root paths defined: %s", k)
 			}
 		}
-	})
-	return loadErr
+	}
+	return mapping, report, nil
 }
 
-// hasPath checks whether a configuration value is present for the provided path.
+// HasPath checks whether a configuration value is present for the provided path.
 //
 // If the path exists, then Value(path) will never result in an error. However, the typed getters, such as
 // Int(path), will return a non-nil error if the value is not convertible to the requested type.
-func (c *configuration) hasPath(path Path) bool {
-	if path.Empty() {
-		return false
+func (c *Config) HasPath(path string) bool {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.hasPath(Path(path))
+}
+
+// Root returns the root configuration Path, which is empty if the configuration is rootless.
+func (c *Config) Root() Path {
+	return c.root
+}
+
+// Set sets or replaces a configuration value for the provided path.
+//
+// Returns:
+//   - true if the value corresponding to the provided path was successfully replaced
+//   - false if the configuration does not contain the specified path or otherwise could not replace the value
+func (c *Config) Set(path string, value string) bool {
+	return c.set(Path(path), value)
+}
+
+// Size returns the current number configuration paths within the root Path of the Config.
+func (c *Config) Size() int {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return len(c.scoped())
+}
+
+// Scope returns a view of the configuration rooted at the provided path. Values for the returned Config are
+// retrieved using paths relative to the provided path, and the returned Config shares the configuration mapping of
+// the Config it was created from, including changes from Set and Reload.
+//
+// The returned error will be non-nil if the value corresponding to the provided path could not be found.
+func (c *Config) Scope(path string) (*Config, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	key, ok := c.lookup(Path(path))
+	if !ok {
+		return nil, &PathError{Err: ErrPathNotFound, Operation: "scope", Path: path}
 	}
+	return &Config{store: c.store, root: key}, nil
+}
 
-	path = c.resolve(path)
-	for key := range c.mapping {
-		if key.Equals(path) {
-			return true
-		}
+// Sub returns the direct sub-paths for the provided path, sorted by name.
+func (c *Config) Sub(path string) ([]Path, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	key, ok := c.lookup(Path(path))
+	if !ok {
+		return nil, &PathError{Err: ErrPathNotFound, Operation: "sub", Path: path}
 	}
-	return false
+	return c.children(key, true), nil
 }
 
-func (c *configuration) resolve(path Path) Path {
-	if path.Equals(c.root) {
-		return path
+// String returns a string representation of the configuration.
+func (c *Config) String() string {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+
+	m := make(map[string]any)
+	m["file_paths"] = c.filePaths
+	m["mapping"] = c.scoped()
+	m["root"] = c.root
+	return string(anchor.ToJSONFormatted(m))
+}
+
+// scoped returns the subset of the configuration mapping within the root Path of the Config.
+func (c *Config) scoped() configMap {
+	if c.root.Equals(c.namespace) {
+		return c.mapping
 	}
 
-	if !strings.HasPrefix(path.String(), c.root.String()+".") {
-		path = c.root.Join(path)
+	prefix := strings.ToLower(c.root.String()) + "."
+	mapping := make(configMap)
+	for k, v := range c.mapping {
+		if k.Equals(c.root) || strings.HasPrefix(strings.ToLower(k.String()), prefix) {
+			mapping[k] = v
+		}
 	}
-	return path
+	return mapping
+}
+
+// hasPath checks whether a configuration value is present for the provided path.
+func (c *Config) hasPath(path Path) bool {
+	_, ok := c.lookup(path)
+	return ok
+}
+
+// lookup returns the key in the configuration mapping that matches the provided path, ignoring case. An empty path
+// matches the root path of the Config (e.g. the element of a scalar collection returned by Elements).
+func (c *Config) lookup(path Path) (Path, bool) {
+	if path.Empty() && c.root.Empty() {
+		return "", false
+	}
+
+	return c.mapping.lookup(c.resolve(path))
+}
+
+func (c *Config) resolve(path Path) Path {
+	return resolve(c.root, path)
 }
 
 // isCollection checks whether the configuration value corresponding to the provided Path represents a collection of
@@ -143,7 +352,7 @@ func (c *configuration) resolve(path Path) Path {
 //   - true if the value corresponding to the provided Path represents a collection of mapping
 //   - false if the value corresponding to the provided Path does not represent a collection of mapping
 //   - false if the value corresponding to the Path could not be found
-func (c *configuration) isCollection(path Path) bool {
+func (c *Config) isCollection(path Path) bool {
 	if !c.hasPath(path) {
 		return false
 	}
@@ -151,38 +360,76 @@ func (c *configuration) isCollection(path Path) bool {
 	return h
 }
 
+// children returns the keys in the configuration mapping that are direct sub-paths of the provided key, optionally
+// including collection entries (e.g. `<key>.#` and `<key>.#0`).
+func (c *Config) children(key Path, collection bool) []Path {
+	var prefix string
+	if !key.Empty() {
+		prefix = strings.ToLower(key.String()) + "."
+	}
+
+	var paths []Path
+	for k := range c.mapping {
+		if s := strings.ToLower(k.String()); strings.HasPrefix(s, prefix) {
+			if name := s[len(prefix):]; !strings.Contains(name, ".") && (collection || !strings.HasPrefix(name, "#")) {
+				paths = append(paths, k)
+			}
+		}
+	}
+	slices.SortFunc(paths, func(a, b Path) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return paths
+}
+
 // set sets or replaces a configuration value for the provided Path.
 //
 // Returns:
 //   - true if the value corresponding to the provided Path was successfully replaced
 //   - false if the configuration does not contain the specified Path or otherwise could not replace the value
-func (c *configuration) set(path Path, value string) bool {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
+func (c *Config) set(path Path, value string) bool {
+	if path.Empty() {
+		return false
+	}
 
-	if !path.Empty() {
-		c.mapping[c.resolve(path)] = value
-		return true
+	c.mutex.Lock()
+	path = c.resolve(path)
+	if key, found := c.mapping.lookup(path); found {
+		path = key // retain the existing case of the path
+	}
+	old, ok := c.mapping[path]
+	c.mapping[path] = value
+	c.mutex.Unlock()
+
+	change := Change{Path: path, Old: old, New: value}
+	if !ok {
+		c.notify(Diff{Added: []Change{change}})
+	} else if old != value {
+		c.notify(Diff{Changed: []Change{change}})
 	}
-	return false
+	return true
 }
 
 // value retrieves the configuration value for the provided path.
 //
 // The returned error will be non-nil if the value corresponding to the provided path could not be found.
-func (c *configuration) value(path Path) (string, error) {
-	if !c.hasPath(path) {
+func (c *Config) value(path Path) (string, error) {
+	key, ok := c.lookup(path)
+	if !ok {
 		return "", &PathError{Err: ErrPathNotFound, Operation: "value", Path: path.String()}
 	}
-	return c.mapping[c.resolve(path)], nil
+	return c.mapping[key], nil
 }
 
-// values retrieves the collection of configuration mapping for the provided path.
+// elements retrieves the keys for the elements of the collection of configuration mapping for the provided path, in
+// the order the elements were declared. The number of elements is determined by the collection length entry (e.g.
+// `<path>.#`).
 //
 // The returned error will be non-nil if the value corresponding to the path:
 //   - could not be found
 //   - was found but does not map to a collection of mapping (e.g. slice)
-func (c *configuration) values(path Path) ([]string, error) {
+//   - was found, but the collection length or any of its elements could not be found
+func (c *Config) elements(path Path) ([]Path, error) {
 	if !c.hasPath(path) {
 		return nil, &PathError{Err: ErrPathNotFound, Operation: "values", Path: path.String()}
 	}
@@ -191,130 +438,168 @@ func (c *configuration) values(path Path) ([]string, error) {
 		return nil, &PathError{Err: errors.New("value does not represent a collection"), Path: path.String()}
 	}
 
-	var values []string
-	slicePrefixPattern := regexp.MustCompile(fmt.Sprintf(formatSlicePrefix, path))
-	for p := range c.mapping {
-		if slicePrefixPattern.MatchString(p.String()) {
-			values = append(values, c.mapping[p])
-		}
+	key, _ := c.lookup(path)
+	n, err := strconv.Atoi(c.mapping[Path(fmt.Sprintf(formatSliceSuffix, key))])
+	if err != nil {
+		return nil, &PathError{Err: fmt.Errorf("invalid collection length: %w", err), Operation: "values", Path: path.String()}
 	}
-	return values, nil
-}
 
-// Size returns the current number configuration paths.
-func Size() int {
-	config.mutex.RLock()
-	defer config.mutex.RUnlock()
-	return len(config.mapping)
+	keys := make([]Path, n)
+	for i := range keys {
+		keys[i] = Path(fmt.Sprintf(formatSliceElement, key, i))
+		if _, ok := c.mapping[keys[i]]; !ok {
+			return nil, &PathError{Err: ErrPathNotFound, Operation: "values", Path: keys[i].String()}
+		}
+	}
+	return keys, nil
 }
 
-// String returns a string representation of the configuration.
-func (c *configuration) String() string {
-	c.mutex.RLock()
-	defer c.mutex.RUnlock()
+// values retrieves the collection of configuration mapping for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the path:
+//   - could not be found
+//   - was found but does not map to a collection of mapping (e.g. slice)
+func (c *Config) values(path Path) ([]string, error) {
+	keys, err := c.elements(path)
+	if err != nil {
+		return nil, err
+	}
 
-	m := make(map[string]any)
-	m["file_path"] = c.filePath
-	m["mapping"] = c.mapping
-	m["root"] = c.root
-	return string(anchor.ToJSONFormatted(m))
+	values := make([]string, len(keys))
+	for i, key := range keys {
+		values[i] = c.mapping[key]
+	}
+	return values, nil
 }
 
-// HasPath checks whether a configuration value is present for the provided path.
+// HasPath checks whether a configuration value is present for the provided path in the default configuration.
 func HasPath(path string) (bool, error) {
-	if config == nil {
-		return false, fmt.Errorf("configuration: %w", ErrNotInitialized)
+	c, err := instance()
+	if err != nil {
+		return false, err
+	}
+	return c.HasPath(path), nil
+}
+
+// Reload re-reads and parses the default configuration.
+//
+// If an error occurs during read/parse operations, error will be non-nil and the current configuration is retained.
+func Reload() error {
+	c, err := instance()
+	if err != nil {
+		return err
 	}
-	return config.hasPath(Path(path)), nil
+	return c.Reload()
 }
 
-// Root returns the root configuration Path.
+// Root returns the root configuration Path of the default configuration.
 func Root() Path {
-	if config == nil {
-		panic(fmt.Errorf("configuration: %w", ErrNotInitialized))
+	c, err := instance()
+	if err != nil {
+		panic(err)
 	}
-	return config.root
+	return c.Root()
 }
 
-// Set sets or replaces a configuration value for the provided path.
+// Set sets or replaces a configuration value for the provided path in the default configuration.
 //
 // Returns:
 //   - true if the value corresponding to the provided path was successfully replaced
 //   - false if the configuration does not contain the specified path or otherwise could not replace the value
 func Set(path string, value string) (bool, error) {
-	if config == nil {
-		return false, fmt.Errorf("configuration: %w", ErrNotInitialized)
+	c, err := instance()
+	if err != nil {
+		return false, err
 	}
-	return config.set(Path(path), value), nil
+	return c.Set(path, value), nil
 }
 
-// Sub returns the sub-paths for the provided path.
-func Sub(path string) ([]Path, error) {
-	if config == nil {
-		return nil, fmt.Errorf("configuration: %w", ErrNotInitialized)
+// Size returns the current number configuration paths of the default configuration.
+func Size() int {
+	c, err := instance()
+	if err != nil {
+		return 0
 	}
+	return c.Size()
+}
 
-	hasPath, err := HasPath(path)
+// Scope returns a view of the default configuration rooted at the provided path.
+//
+// See Config.Scope for how values are retrieved from the returned Config.
+func Scope(path string) (*Config, error) {
+	c, err := instance()
 	if err != nil {
 		return nil, err
 	}
+	return c.Scope(path)
+}
 
-	if !hasPath {
-		return nil, &PathError{Err: ErrPathNotFound, Operation: "sub", Path: path}
+// Sub returns the direct sub-paths for the provided path in the default configuration, sorted by name.
+func Sub(path string) ([]Path, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
 	}
+	return c.Sub(path)
+}
 
-	p := config.resolve(Path(path))
-	d := p.Depth() + 1
-
-	var paths []Path
-	for key := range config.mapping {
-		if strings.Contains(key.String(), p.String()) && key.Depth() == d {
-			paths = append(paths, key)
-		}
+// String returns a string representation of the default configuration.
+func String() string {
+	c, err := instance()
+	if err != nil {
+		return err.Error()
 	}
-	return paths, nil
+	return c.String()
 }
 
-// String returns a string representation of the configuration.
-func String() string {
-	if config == nil {
-		return fmt.Errorf("configuration: %w", ErrNotInitialized).Error()
+// instance returns the default configuration, or a non-nil error if it has not been initialized using Load.
+func instance() (*Config, error) {
+	c := config.Load()
+	if c == nil {
+		return nil, fmt.Errorf("configuration: %w", ErrNotInitialized)
 	}
-	return config.String()
+	return c, nil
 }
 
-func readConfig(filePath string) (map[string]any, error) {
-	fileExtension := regexp.MustCompile(fileExtensionPattern).FindString(filePath)
-	switch fileExtension {
-	case ".json":
-		return nil, nil
-	case ".yaml", ".yml":
-		return readConfigAndThen(filePath, readYaml)
-	default:
-		return nil, errors.New(fmt.Sprintf(
-			"configuration: unsupported file type, expected one of %s, but found %s for path %s",
-			[]string{".json", ".yaml", ".yml"}, fileExtension, filePath))
+// resolve returns the provided path prefixed with the provided root Path, unless the path already begins with it.
+func resolve(root Path, path Path) Path {
+	if path.Equals(root) {
+		return path
+	}
+
+	if !strings.HasPrefix(path.String(), root.String()+".") {
+		path = root.Join(path)
 	}
+	return path
 }
 
-func readConfigAndThen(filePath string, mapConfigFn mapConfigFunc) (map[string]any, error) {
-	if strings.TrimSpace(filePath) == "" {
-		return nil, errors.New("configuration: file path cannot be empty")
+// withProfiles returns the provided files, each followed by the optional file for each of the provided profiles.
+func withProfiles(files []configFile, profiles []string) []configFile {
+	if len(profiles) == 0 {
+		return files
 	}
 
-	bytes, err := os.ReadFile(filePath)
-	if err != nil {
-		return nil, fmt.Errorf("configuration: %w", err)
+	fileExtension := regexp.MustCompile(fileExtensionPattern)
+	result := make([]configFile, 0, len(files)*(len(profiles)+1))
+	for _, f := range files {
+		result = append(result, f)
+
+		ext := fileExtension.FindString(f.path)
+		for _, profile := range profiles {
+			result = append(result, configFile{path: strings.TrimSuffix(f.path, ext) + "-" + profile + ext})
+		}
 	}
-	return mapConfigFn(bytes)
+	return result
 }
 
-func readYaml(bytes []byte) (map[string]any, error) {
-	var yamlConfig map[string]any
-	if err := yaml.Unmarshal(bytes, &yamlConfig); err != nil {
-		return nil, fmt.Errorf("configuration: could not read YAML Configuration: %w", err)
+func splitProfiles(profiles string) []string {
+	var result []string
+	for _, p := range strings.Split(profiles, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
 	}
-	return yamlConfig, nil
+	return result
 }
 
 func interpolate(pattern *regexp.Regexp, template string, value string) string {
@@ -1,12 +1,25 @@
 package config
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"maps"
+	"net/netip"
+	"net/url"
 	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/multiformats/go-multiaddr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
 )
@@ -31,11 +44,11 @@ func (t *ConfigTestSuite) SetupTest() {
 		t.T().Fatal(err)
 	}
 
-	if err := Load(WithFilePath(testConfigFile)); err != nil {
+	if err := Load(WithFilePath(testConfigFile)); err != nil && !errors.Is(err, ErrAlreadyLoaded) {
 		t.T().Fatal(err)
 	}
 
-	fmt.Printf("test configuration:\n%s\n", config.String())
+	fmt.Printf("test configuration:\n%s\n", String())
 
 	assert.Equal(t.T(), Root().String(), defaultRoot)
 	assert.Equal(t.T(), 13, Size())
@@ -60,3 +73,1026 @@ func (t *ConfigTestSuite) TestConfig_Values() {
 	assert.Equal(t.T(), TimeMustResolve(v.Join("time").String()).Format(time.RFC3339), strings.TrimSpace(testEnvVarTimeValue))
 	assert.Equal(t.T(), URLMustResolve(v.Join("url").String()).String(), "https://example.com:9003")
 }
+
+func (t *ConfigTestSuite) TestConfig_LoadAgain() {
+	assert.NoError(t.T(), Load())
+	assert.ErrorIs(t.T(), Load(WithFilePath(testDataDir+"/layered/base.yaml")), ErrAlreadyLoaded)
+	assert.Equal(t.T(), "test-app", ValueMustResolve("application.name"))
+}
+
+func TestConfig_ReadJSON(t *testing.T) {
+	rawConfig, err := readConfig(testDataDir + "/application.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := newConfigMap(rawConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "test-app", mapping["config.application.name"])
+	assert.Equal(t, "true", mapping["config.value.bool"])
+	assert.Equal(t, "1.168", mapping["config.value.float"])
+	assert.Equal(t, "138", mapping["config.value.int"])
+	assert.Contains(t, mapping, Path("config.value.null"))
+	assert.Empty(t, mapping["config.value.null"])
+	assert.Equal(t, "2", mapping["config.value.hosts.#"])
+	assert.Equal(t, "b.example.com", mapping["config.value.hosts.#1"])
+	assert.Equal(t, "2", mapping["config.value.matrix.#0.#"])
+	assert.Equal(t, "3", mapping["config.value.matrix.#1.#0"])
+
+	_, err = readJson([]byte(`{"config": {"a": 1}} {"config": {"b": 2}}`))
+	assert.ErrorContains(t, err, "unexpected data")
+
+	_, err = readJson([]byte(`{"config": {"a": 1}} garbage`))
+	assert.Error(t, err)
+
+	_, err = readJson([]byte("{\"config\": {\"a\": 1}}\n"))
+	assert.NoError(t, err)
+}
+
+func TestConfig_ReadTOML(t *testing.T) {
+	rawConfig, err := readConfig(testDataDir + "/application.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := newConfigMap(rawConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "test-app", mapping["config.application.name"])
+	assert.Equal(t, "true", mapping["config.value.bool"])
+	assert.Equal(t, "30s", mapping["config.value.duration"])
+	assert.Equal(t, "1.168", mapping["config.value.float"])
+	assert.Equal(t, "138", mapping["config.value.int"])
+	assert.Equal(t, "2025-05-13T01:38:00Z", mapping["config.value.time"])
+	assert.Equal(t, "2", mapping["config.servers.#"])
+	assert.Equal(t, "b.example.com", mapping["config.servers.#1.host"])
+	assert.Equal(t, "9004", mapping["config.servers.#1.port"])
+	assert.Equal(t, "1979-05-27T07:32:00", mapping["config.local.datetime"])
+	assert.Equal(t, "1979-05-27", mapping["config.local.date"])
+	assert.Equal(t, "07:32:00.5", mapping["config.local.time"])
+	assert.Equal(t, "07:32:00", mapping["config.local.times.#0"])
+	assert.Equal(t, "1979-05-27", mapping["config.local.times.#1"])
+}
+
+func TestConfig_RegisterFormat(t *testing.T) {
+	_, err := readConfig(testDataDir + "/application.properties")
+	assert.ErrorContains(t, err, "unsupported file type")
+
+	err = RegisterFormat("properties", func(b []byte) (map[string]any, error) {
+		m := make(map[string]any)
+		for _, line := range strings.Split(string(b), "\n") {
+			if k, v, ok := strings.Cut(line, "="); ok {
+				m[k] = v
+			}
+		}
+		return m, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		formatMutex.Lock()
+		defer formatMutex.Unlock()
+		delete(formats, ".properties")
+	})
+	assert.Contains(t, formatExtensions(), ".properties")
+
+	rawConfig, err := readConfig(testDataDir + "/application.properties")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mapping, err := newConfigMap(rawConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "test-app", mapping["config.application.name"])
+	assert.Error(t, RegisterFormat("", readYaml))
+	assert.Error(t, RegisterFormat(".yaml", nil))
+}
+
+func TestConfig_New(t *testing.T) {
+	yamlConfig, err := New(WithFilePath(testConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jsonConfig, err := New(WithFilePath(testDataDir + "/application.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "30s", yamlConfig.ValueMustResolve("value.duration"))
+	assert.False(t, jsonConfig.HasPath("value.duration"))
+	assert.Equal(t, 138, jsonConfig.IntMustResolve("value.int"))
+
+	assert.True(t, jsonConfig.Set("value.int", "139"))
+	assert.Equal(t, 139, jsonConfig.IntMustResolve("value.int"))
+	assert.Equal(t, 138, yamlConfig.IntMustResolve("value.int"))
+}
+
+func TestConfig_Reload(t *testing.T) {
+	filePath := t.TempDir() + "/application.yaml"
+	if err := os.WriteFile(filePath, []byte("config:\n  value:\n    int: ${TEST_APP_RELOAD_INT | 1}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(WithFilePath(filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, c.IntMustResolve("value.int"))
+
+	t.Setenv("TEST_APP_RELOAD_INT", "2")
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, c.IntMustResolve("value.int"))
+
+	if err := os.WriteFile(filePath, []byte("config:\n  value: [\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	assert.Error(t, c.Reload())
+	assert.Equal(t, 2, c.IntMustResolve("value.int"))
+}
+
+func TestConfig_ReloadConcurrent(t *testing.T) {
+	var reads atomic.Int64
+	c, err := New(WithSource(NewSource("counter", func() (map[string]any, error) {
+		n := reads.Add(1)
+		time.Sleep(time.Duration(n%3) * time.Millisecond)
+		return map[string]any{"config": map[string]any{"reads": n}}, nil
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var changes []string
+	c.Watch("reads", func(d Diff) {
+		for _, change := range d.Changed {
+			changes = append(changes, change.New)
+		}
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, c.Reload())
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 11, c.IntMustResolve("reads"))
+	if assert.Len(t, changes, 10) {
+		for i, v := range changes {
+			assert.Equal(t, strconv.Itoa(i+2), v)
+		}
+	}
+}
+
+func TestConfig_WatchFile(t *testing.T) {
+	filePath := t.TempDir() + "/application.yaml"
+	if err := os.WriteFile(filePath, []byte("config:\n  value:\n    int: 1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(WithFilePath(filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.WatchFile(ctx, WithWatchInterval(5*time.Millisecond), WithWatchDebounce(10*time.Millisecond))
+
+	time.Sleep(20 * time.Millisecond)
+	if err := os.WriteFile(filePath, []byte("config:\n  value:\n    int: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Eventually(t, func() bool {
+		return c.IntMustResolve("value.int") == 2
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestConfig_WatchFileAdded(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(dir+"/application.d", 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(
+		WithDefaults(map[string]any{"value.int": 1, "value.bool": false}),
+		WithSearchPaths(dir+"/local", dir),
+		WithDropInDirs(dir+"/application.d"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.WatchFile(ctx, WithWatchInterval(5*time.Millisecond), WithWatchDebounce(10*time.Millisecond))
+
+	time.Sleep(20 * time.Millisecond)
+	if err := os.WriteFile(dir+"/application.d/10-value.yaml", []byte("config:\n  value:\n    int: 2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	assert.Eventually(t, func() bool {
+		return c.IntMustResolve("value.int") == 2
+	}, time.Second, 5*time.Millisecond)
+
+	if err := os.Mkdir(dir+"/local", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dir+"/local/application.yaml", []byte("config:\n  value:\n    bool: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	assert.Eventually(t, func() bool {
+		return c.BoolMustResolve("value.bool")
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestConfig_WatchOptions(t *testing.T) {
+	opts := &WatchOption{debounce: defaultWatchDebounce, interval: defaultWatchInterval, onError: func(error) {}}
+	for _, opt := range []func(*WatchOption){
+		WithWatchInterval(0),
+		WithWatchInterval(-time.Second),
+		WithWatchDebounce(-time.Second),
+		WithWatchErrorHandler(nil),
+	} {
+		opt(opts)
+	}
+	assert.Equal(t, defaultWatchInterval, opts.interval)
+	assert.Equal(t, defaultWatchDebounce, opts.debounce)
+	assert.NotNil(t, opts.onError)
+
+	c, err := New(WithFilePath(testConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.NotPanics(t, func() {
+		c.WatchFile(ctx, WithWatchInterval(0), WithWatchErrorHandler(nil))
+	})
+}
+
+func TestConfig_Watch(t *testing.T) {
+	filePath := t.TempDir() + "/application.yaml"
+	if err := os.WriteFile(filePath, []byte("config:\n  workers:\n    count: 1\n  other: a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := New(WithFilePath(filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var diffs []Diff
+	cancel := c.Watch("workers", func(d Diff) {
+		diffs = append(diffs, d)
+	})
+
+	c.Set("other", "b")
+	assert.Empty(t, diffs)
+
+	c.Set("workers.count", "2")
+	if assert.Len(t, diffs, 1) {
+		assert.Equal(t, []Change{{Path: "config.workers.count", Old: "1", New: "2"}}, diffs[0].Changed)
+	}
+
+	if err := os.WriteFile(filePath, []byte("config:\n  workers:\n    size: 4\n  other: a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, diffs, 2) {
+		assert.Equal(t, []Change{{Path: "config.workers.size", New: "4"}}, diffs[1].Added)
+		assert.Equal(t, []Change{{Path: "config.workers.count", Old: "2"}}, diffs[1].Removed)
+		assert.Empty(t, diffs[1].Changed)
+	}
+
+	cancel()
+	c.Set("workers.size", "8")
+	assert.Len(t, diffs, 2)
+}
+
+func TestConfig_Bind(t *testing.T) {
+	type peer struct {
+		Host string
+		Port uint16 `config:"port"`
+	}
+
+	type tls struct {
+		Enabled bool `config:"enabled"`
+	}
+
+	var server struct {
+		Name     string
+		Timeout  time.Duration       `config:"timeout"`
+		MaxBody  int64               `config:"maxBodySize,size"`
+		Endpoint *url.URL            `config:"endpoint"`
+		Addr     multiaddr.Multiaddr `config:"addr"`
+		Started  time.Time           `config:"started"`
+		IP       netip.Addr          `config:"ip"`
+		TLS      *tls                `config:"tls"`
+		Weights  map[string]float64  `config:"weights"`
+		Peers    []peer              `config:"peers"`
+		Retries  []time.Duration     `config:"retries"`
+		Missing  string              `config:"missing"`
+		Skipped  string              `config:"-"`
+	}
+	server.Missing = "unchanged"
+
+	c, err := New(WithFilePath(testDataDir + "/bind.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Bind("server", &server); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "test-app", server.Name)
+	assert.Equal(t, 30*time.Second, server.Timeout)
+	assert.Equal(t, int64(1048576), server.MaxBody)
+	assert.Equal(t, "https://example.com:9003", server.Endpoint.String())
+	assert.Equal(t, "/dns4/example.com/tcp/9003", server.Addr.String())
+	assert.Equal(t, "2025-05-13T01:38:00Z", server.Started.Format(time.RFC3339))
+	assert.Equal(t, netip.MustParseAddr("192.168.1.10"), server.IP)
+	assert.True(t, server.TLS.Enabled)
+	assert.Equal(t, map[string]float64{"a": 1.5, "b": 2}, server.Weights)
+	assert.Equal(t, []peer{{Host: "a.example.com", Port: 9003}, {Host: "b.example.com", Port: 9004}}, server.Peers)
+	assert.Equal(t, []time.Duration{time.Second, 5 * time.Second}, server.Retries)
+	assert.Equal(t, "unchanged", server.Missing)
+
+	var unsigned struct {
+		Port uint16 `config:"port"`
+		TTL  uint8  `config:"ttl"`
+		Max  uint64 `config:"max"`
+	}
+	grpc, err := New(WithBytes([]byte("config: {grpc: {port: 50051, ttl: 200, max: 18446744073709551615}}"), "yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := grpc.Bind("grpc", &unsigned); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, uint16(50051), unsigned.Port)
+	assert.Equal(t, uint8(200), unsigned.TTL)
+	assert.Equal(t, uint64(18446744073709551615), unsigned.Max)
+
+	var small uint8
+	assert.ErrorIs(t, grpc.Bind("grpc.port", &small), strconv.ErrRange)
+
+	var timeout int
+	assert.Error(t, c.Bind("server.timeout", &timeout))
+	assert.Error(t, c.Bind("server", server))
+	assert.ErrorIs(t, c.Bind("server.none", &timeout), ErrPathNotFound)
+}
+
+func TestConfig_Get(t *testing.T) {
+	type id string
+
+	c, err := New(WithFilePath(testDataDir + "/bind.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 30*time.Second, MustGetFrom[time.Duration](c, "server.timeout"))
+	assert.Equal(t, netip.MustParseAddr("192.168.1.10"), MustGetFrom[netip.Addr](c, "server.ip"))
+
+	_, err = GetFrom[id](c, "server.name")
+	assert.ErrorContains(t, err, "no decoder registered")
+
+	if err := RegisterDecoder(func(v string) (id, error) {
+		return id("id-" + v), nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		decoderMutex.Lock()
+		defer decoderMutex.Unlock()
+		delete(decoders, reflect.TypeFor[id]())
+	})
+	assert.Equal(t, id("id-test-app"), MustGetFrom[id](c, "server.name"))
+
+	_, err = GetFrom[int](c, "server.name")
+	assert.ErrorIs(t, err, strconv.ErrSyntax)
+	assert.Error(t, RegisterDecoder[id](nil))
+
+	if err := RegisterDecoder(func(string) (fmt.Stringer, error) {
+		return nil, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		decoderMutex.Lock()
+		defer decoderMutex.Unlock()
+		delete(decoders, reflect.TypeFor[fmt.Stringer]())
+	})
+	s, err := GetFrom[fmt.Stringer](c, "server.name")
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestConfig_Collections(t *testing.T) {
+	c, err := New(WithFilePath(testDataDir + "/collection.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []time.Duration{time.Second, 5 * time.Second, 30 * time.Second}, c.DurationsMustResolve("retries"))
+	assert.Equal(t, []int64{1024, 1048576}, c.SizeBytesListMustResolve("sizes"))
+	assert.Equal(t, "/dns4/b.example.com/tcp/9003", c.MultiaddrsMustResolve("peers")[1].String())
+	assert.Equal(t, []string{"9003", "invalid"}, c.StringsMustResolve("ports"))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}, c.IntsMustResolve("hosts.order"))
+
+	_, err = c.Ints("ports")
+	var pathErr *PathError
+	if assert.ErrorAs(t, err, &pathErr) {
+		assert.Equal(t, "config.ports.#1", pathErr.Path)
+	}
+
+	_, err = c.Values("peers.#0")
+	assert.ErrorContains(t, err, "does not represent a collection")
+
+	if err := RegisterDecoder(func(string) (fmt.Stringer, error) {
+		return nil, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		decoderMutex.Lock()
+		defer decoderMutex.Unlock()
+		delete(decoders, reflect.TypeFor[fmt.Stringer]())
+	})
+	assert.Equal(t, []fmt.Stringer{nil, nil}, MustGetAllFrom[fmt.Stringer](c, "ports"))
+}
+
+func TestConfig_Elements(t *testing.T) {
+	c, err := New(WithFilePath(testDataDir + "/collection.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	servers, err := c.Elements("servers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, servers, 2) {
+		assert.Equal(t, Path("config.servers.#1"), servers[1].Root())
+		assert.Equal(t, "b.example.com", servers[1].ValueMustResolve("host"))
+		assert.Equal(t, 9004, servers[1].IntMustResolve("port"))
+		assert.Equal(t, 0.25, servers[1].FloatMustResolve("weight"))
+		assert.Equal(t, 4, servers[1].Size())
+	}
+
+	var hosts []string
+	err = c.Each("servers", func(i int, server *Config) error {
+		hosts = append(hosts, server.ValueMustResolve("host"))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a.example.com", "b.example.com"}, hosts)
+
+	retries, err := c.Elements("retries")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, retries, 3) {
+		assert.Equal(t, "5s", retries[1].ValueMustResolve(""))
+		assert.Equal(t, 30*time.Second, retries[2].DurationMustResolve(""))
+	}
+
+	_, err = c.Elements("servers.#0.host")
+	assert.Error(t, err)
+
+	rootless, err := New(WithFilePath(testDataDir+"/rootless.yaml"), WithoutRoot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = rootless.Value("")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+}
+
+func TestConfig_Scope(t *testing.T) {
+	c, err := New(WithFilePath(testDataDir + "/scope.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub, err := c.Sub("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Path{"config.db.host", "config.db.pool", "config.db.port"}, sub)
+
+	db, err := c.Scope("db")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, Path("config.db"), db.Root())
+	assert.Equal(t, "db.example.com", db.ValueMustResolve("host"))
+	assert.Equal(t, 8, db.IntMustResolve("pool.size"))
+	assert.False(t, db.HasPath("dbx.host"))
+	assert.Equal(t, 5, db.Size())
+
+	pool, err := db.Scope("pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Set("db.pool.size", "16")
+	assert.Equal(t, 16, pool.IntMustResolve("size"))
+
+	_, err = c.Scope("none")
+	assert.ErrorIs(t, err, ErrPathNotFound)
+}
+
+func TestConfig_Root(t *testing.T) {
+	_, err := New(WithFilePath(testDataDir + "/rootless.yaml"))
+	assert.ErrorContains(t, err, "multiple root paths defined")
+
+	c, err := New(WithFilePath(testDataDir+"/rootless.yaml"), WithRoot("server"))
+	assert.ErrorContains(t, err, "multiple root paths defined")
+
+	c, err = New(WithFilePath(testDataDir+"/rootless.yaml"), WithoutRoot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, c.Root().Empty())
+	assert.Equal(t, 8080, c.IntMustResolve("server.port"))
+	assert.Equal(t, "info", c.ValueMustResolve("logging.level"))
+	assert.Equal(t, 5, c.Size())
+
+	sub, err := c.Sub("server")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []Path{"server.host", "server.port"}, sub)
+
+	var cfg struct {
+		Server struct {
+			Port int
+		}
+		Logging struct {
+			Level string
+		}
+	}
+	if err := c.Bind("", &cfg); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 8080, cfg.Server.Port)
+	assert.Equal(t, "info", cfg.Logging.Level)
+
+	filePath := t.TempDir() + "/application.yaml"
+	if err := os.WriteFile(filePath, []byte("app:\n  name: test-app\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = New(WithFilePath(filePath), WithRoot("app"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, Path("app"), c.Root())
+	assert.Equal(t, "test-app", c.ValueMustResolve("name"))
+	assert.Equal(t, "test-app", c.ValueMustResolve("app.name"))
+}
+
+func TestConfig_Layered(t *testing.T) {
+	c, err := New(
+		WithFilePaths(testDataDir+"/layered/base.yaml"),
+		WithOptionalFilePaths(testDataDir+"/layered/missing.yaml"),
+		WithFilePaths(testDataDir+"/layered/override.yaml"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "0.0.0.0", c.ValueMustResolve("server.host"))
+	assert.Equal(t, 9090, c.IntMustResolve("server.port"))
+	assert.Equal(t, "disabled", c.ValueMustResolve("server.tls"))
+	assert.False(t, c.HasPath("server.tls.cert"))
+	assert.Equal(t, []string{"d.example.com"}, c.StringsMustResolve("peers"))
+	assert.False(t, c.HasPath("peers.#2"))
+	assert.Equal(t, "debug", c.ValueMustResolve("logging.level"))
+	assert.Equal(t, "json", c.ValueMustResolve("logging.format"))
+}
+
+func TestConfig_Profiles(t *testing.T) {
+	filePath := testDataDir + "/profile/application.yaml"
+
+	c, err := New(WithFilePath(filePath), WithProfiles("prod", "eu"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "warn", c.ValueMustResolve("logging.level"))
+	assert.Equal(t, "db.eu.prod.example.com", c.ValueMustResolve("db.host"))
+	assert.Equal(t, 5432, c.IntMustResolve("db.port"))
+
+	t.Setenv(profilesEnvVar, "eu, prod")
+	c, err = New(WithFilePath(filePath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "db.prod.example.com", c.ValueMustResolve("db.host"))
+
+	c, err = New(WithFilePath(filePath), WithProfiles("dev"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "localhost", c.ValueMustResolve("db.host"))
+}
+
+func TestConfig_EnvOverrides(t *testing.T) {
+	t.Setenv("CONFIG_VALUE_INT", "5")
+	t.Setenv("APP__CONFIG__SERVERS__I1__HOST", "c.example.com")
+	t.Setenv("APP__CONFIG__RETRIES__I0", "2s")
+
+	c, err := New(WithFilePath(testConfigFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 138, c.IntMustResolve("value.int"))
+
+	c, err = New(WithFilePath(testConfigFile), WithEnvOverrides(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 5, c.IntMustResolve("value.int"))
+
+	c, err = New(
+		WithFilePath(testDataDir+"/collection.yaml"),
+		WithEnvOverrides("APP"),
+		WithEnvSeparator("__"),
+		WithEnvIndexFormat("I%d"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "c.example.com", c.ValueMustResolve("servers.#1.host"))
+	assert.Equal(t, []time.Duration{2 * time.Second, 5 * time.Second, 30 * time.Second}, c.DurationsMustResolve("retries"))
+
+	t.Setenv(profilesEnvVar, "prod")
+	t.Setenv("APP_CONFIG_PROFILES", "eu")
+	profiles := []byte("config: {profiles: dev}")
+
+	c, err = New(WithBytes(profiles, "yaml"), WithEnvOverrides(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "dev", c.ValueMustResolve("profiles"))
+
+	c, err = New(WithBytes(profiles, "yaml"), WithEnvOverrides("APP"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "eu", c.ValueMustResolve("profiles"))
+}
+
+func TestConfig_Overrides(t *testing.T) {
+	c, err := New(
+		WithFilePath(testConfigFile),
+		WithArgs([]string{"-v", "--set", "value.duration=5s", "--set=config.value.int=7", "run"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 5*time.Second, c.DurationMustResolve("value.duration"))
+	assert.Equal(t, 7, c.IntMustResolve("value.int"))
+
+	var overrides Overrides
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&overrides, "set", "configuration override")
+	fs.String("value.bool", "", "")
+	if err := fs.Parse([]string{"--set", "value.float=2.5", "--value.bool=false"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = New(WithFilePath(testConfigFile), WithFlagSet(fs), WithStrictOverrides())
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2.5, c.FloatMustResolve("value.float"))
+	assert.False(t, c.BoolMustResolve("value.bool"))
+
+	_, err = New(WithFilePath(testConfigFile), WithArgs([]string{"--set", "value.unknown=1"}), WithStrictOverrides())
+	assert.ErrorIs(t, err, ErrPathNotFound)
+
+	_, err = New(WithFilePath(testConfigFile), WithArgs([]string{"--set", "value.int"}))
+	assert.Error(t, err)
+
+	c, err = New(WithFilePath(testConfigFile), WithArgs([]string{"--set", "value.int=2", "--", "--set", "value.int=3"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, c.IntMustResolve("value.int"))
+}
+
+func TestConfig_Sources(t *testing.T) {
+	c, err := New(WithReader(strings.NewReader("config:\n  value:\n    int: 1\n"), "yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, c.IntMustResolve("value.int"))
+	assert.NoError(t, c.Reload())
+	assert.Equal(t, 1, c.IntMustResolve("value.int"))
+
+	c, err = New(
+		WithFS(os.DirFS(testDataDir), "application.json"),
+		WithBytes([]byte(`{"config": {"value": {"bool": false}}}`), ".json"),
+		WithFilePath(testDataDir+"/layered/override.yaml"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "test-app", c.ValueMustResolve("application.name"))
+	assert.False(t, c.BoolMustResolve("value.bool"))
+	assert.Equal(t, 9090, c.IntMustResolve("server.port"))
+
+	_, err = New(WithBytes([]byte("config: {}"), "hcl"))
+	assert.ErrorContains(t, err, "unsupported format")
+
+	_, err = New(WithFS(os.DirFS(testDataDir), "missing.yaml"))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfig_Report(t *testing.T) {
+	c, err := New(
+		WithFilePaths(testDataDir+"/layered/base.yaml"),
+		WithOptionalFilePaths(testDataDir+"/layered/missing.yaml"),
+		WithProfiles("prod"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []SourceReport{
+		{Name: testDataDir + "/layered/base.yaml", Required: true, Status: SourceFound},
+		{Name: testDataDir + "/layered/base-prod.yaml", Status: SourceSkipped},
+		{Name: testDataDir + "/layered/missing.yaml", Status: SourceSkipped},
+		{Name: testDataDir + "/layered/missing-prod.yaml", Status: SourceSkipped},
+	}, c.Report().Sources)
+
+	_, err = New(WithFilePath(testDataDir+"/layered/typo.yaml"), WithFilePath(testDataDir+"/layered/base.yaml"))
+	var loadErr *LoadError
+	if assert.ErrorAs(t, err, &loadErr) {
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		assert.Equal(t, SourceFailed, loadErr.Report.Sources[0].Status)
+		assert.Equal(t, SourceFound, loadErr.Report.Sources[1].Status)
+	}
+}
+
+func TestConfig_SearchPaths(t *testing.T) {
+	dirs := []string{testDataDir + "/discover/missing", testDataDir + "/discover/user", testDataDir + "/discover/system"}
+
+	c, err := New(WithSearchPaths(dirs...))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 9090, c.IntMustResolve("server.port"))
+	assert.False(t, c.HasPath("server.host"))
+	assert.Equal(t, []SourceReport{
+		{Name: filepath.Join(testDataDir, "discover", "user", defaultFilePath), Status: SourceFound},
+	}, c.Report().Sources)
+
+	c, err = New(WithSearchPaths(dirs...), WithSearchMode(SearchAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 9090, c.IntMustResolve("server.port"))
+	assert.Equal(t, "0.0.0.0", c.ValueMustResolve("server.host"))
+	assert.Equal(t, "info", c.ValueMustResolve("logging.level"))
+
+	c, err = New(WithSearchPaths(testDataDir), WithFileName("scope.yaml"), WithSearchMode(SearchAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, c.Report().Sources, 1)
+
+	c, err = New(WithSearchPaths(testDataDir+"/discover/missing"), WithOptionalFilePaths(testDataDir+"/missing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Zero(t, c.Size())
+}
+
+func TestConfig_DropInDirs(t *testing.T) {
+	dir := filepath.Join(testDataDir, "layered", "application.d")
+
+	c, err := New(
+		WithFilePath(testDataDir+"/layered/base.yaml"),
+		WithDropInDirs(dir, testDataDir+"/layered/missing.d"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "0.0.0.0", c.ValueMustResolve("server.host"))
+	assert.Equal(t, 9443, c.IntMustResolve("server.port"))
+	assert.Equal(t, "disabled", c.ValueMustResolve("server.tls"))
+	assert.Equal(t, "warn", c.ValueMustResolve("logging.level"))
+
+	var names []string
+	for _, s := range c.Report().Sources {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{
+		testDataDir + "/layered/base.yaml",
+		filepath.Join(dir, "10-server.yaml"),
+		filepath.Join(dir, "20-logging.json"),
+	}, names)
+}
+
+func TestConfig_SecretDir(t *testing.T) {
+	c, err := New(
+		WithFilePath(testDataDir+"/application.yaml"),
+		WithSecretDir(testDataDir+"/secrets", "config"),
+		WithSecretDir(testDataDir+"/missing", "config"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "s3cr3t", c.ValueMustResolve("db.password"))
+	assert.Equal(t, "p${ass}word", c.ValueMustResolve("db.token"))
+	assert.Equal(t, "abc123", c.ValueMustResolve("api_key"))
+	assert.True(t, c.HasPath("db"))
+	assert.False(t, c.HasPath(".hidden"))
+	assert.Equal(t, "test-app", c.ValueMustResolve("application.name"))
+	assert.Equal(t, SourceSkipped, c.Report().Sources[2].Status)
+
+	c, err = New(WithFilePath(testDataDir+"/application.yaml"), WithSecretDir(testDataDir+"/secrets", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "abc123", c.ValueMustResolve("config.api_key"))
+
+	c, err = New(WithFilePath(testDataDir+"/application.yaml"), WithSecretDir(testDataDir+"/secrets", "vault"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "s3cr3t", c.ValueMustResolve("vault.db.password"))
+
+	c, err = New(WithoutRoot(), WithSecretDir(testDataDir+"/secrets", "secrets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "abc123", c.ValueMustResolve("secrets.api_key"))
+}
+
+type staticSource map[string]any
+
+func (s staticSource) Name() string {
+	return "static"
+}
+
+func (s staticSource) Read() (map[string]any, error) {
+	return s, nil
+}
+
+func TestConfig_Source(t *testing.T) {
+	reads := 0
+	remote := NewSource("remote", func() (map[string]any, error) {
+		reads++
+		return map[string]any{"config": map[string]any{"server": map[string]any{"port": 8000 + reads}}}, nil
+	})
+	missing := NewSource("missing", func() (map[string]any, error) {
+		return nil, fmt.Errorf("remote: %w", os.ErrNotExist)
+	})
+
+	c, err := New(
+		WithSource(staticSource{"config": map[string]any{"logging": map[string]any{"format": "text", "color": "auto"}}}),
+		WithFilePath(testDataDir+"/layered/override.yaml"),
+		WithSource(remote),
+		WithOptionalSource(missing),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 8001, c.IntMustResolve("server.port"))
+	assert.Equal(t, "disabled", c.ValueMustResolve("server.tls"))
+	assert.Equal(t, "json", c.ValueMustResolve("logging.format"))
+	assert.Equal(t, "auto", c.ValueMustResolve("logging.color"))
+	assert.Equal(t, []SourceReport{
+		{Name: "static", Required: true, Status: SourceFound},
+		{Name: testDataDir + "/layered/override.yaml", Required: true, Status: SourceFound},
+		{Name: "remote", Required: true, Status: SourceFound},
+		{Name: "missing", Status: SourceSkipped},
+	}, c.Report().Sources)
+
+	assert.NoError(t, c.Reload())
+	assert.Equal(t, 8002, c.IntMustResolve("server.port"))
+
+	_, err = New(WithSource(missing))
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	t.Cleanup(func() {
+		defaultsMutex.Lock()
+		defer defaultsMutex.Unlock()
+		defaults = nil
+	})
+
+	assert.NoError(t, SetDefault("server.port", 1))
+	assert.NoError(t, SetDefault("config.server.timeout", time.Second))
+	assert.NoError(t, SetDefault("server.timeout", 5*time.Second))
+	assert.NoError(t, SetDefault("logging.level", "trace"))
+	assert.Error(t, SetDefault(" ", 1))
+	assert.Error(t, SetDefault("server.unsupported", struct{}{}))
+
+	c, err := New(
+		WithDefaults(map[string]any{
+			"logging":         map[string]any{"format": "text"},
+			"metrics.enabled": true,
+			"server.port":     2,
+		}),
+		WithFilePath(testDataDir+"/layered/base.yaml"),
+		WithDefaults(map[string]any{"server.host": "127.0.0.1"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 8080, c.IntMustResolve("server.port"))
+	assert.Equal(t, "127.0.0.1", c.ValueMustResolve("server.host"))
+	assert.Equal(t, 5*time.Second, c.DurationMustResolve("server.timeout"))
+	assert.Equal(t, "info", c.ValueMustResolve("logging.level"))
+	assert.Equal(t, "text", c.ValueMustResolve("logging.format"))
+	assert.True(t, c.BoolMustResolve("metrics.enabled"))
+	var names []string
+	for _, r := range c.Report().Sources {
+		names = append(names, r.Name)
+	}
+	assert.Equal(t, []string{defaultsName, defaultsName, testDataDir + "/layered/base.yaml", defaultsName}, names)
+
+	t.Setenv("CONFIG_VALUE_INT", "5")
+	c, err = New(WithDefaults(map[string]any{"value.int": 1}), WithEnvOverrides(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 5, c.IntMustResolve("value.int"))
+
+	c, err = New(WithEnvOverrides(""), WithDefaults(map[string]any{"value.int": 1}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, c.IntMustResolve("value.int"))
+
+	assert.True(t, c.Set("server.timeout", "1s"))
+	assert.Equal(t, time.Second, c.DurationMustResolve("server.timeout"))
+	assert.NoError(t, c.Reload())
+	assert.Equal(t, 5*time.Second, c.DurationMustResolve("server.timeout"))
+
+	_, err = New(WithFilePath(testDataDir+"/layered/base.yaml"), WithDefaults(map[string]any{"bad": struct{}{}}))
+	var loadErr *LoadError
+	assert.ErrorAs(t, err, &loadErr)
+}
+
+func TestConfig_Merge(t *testing.T) {
+	c, err := New(
+		WithBytes([]byte("config: {Value: {Int: 1, Bool: true}, x: [1, 2], y: {a: 1}, z: {a: 1}}"), "yaml"),
+		WithBytes([]byte("config: {value: {int: 2}, X: {a: 1}, y: [3], z: 4}"), "yaml"),
+		WithBytes([]byte("config: {value: {}, x: {}, z: {}}"), "yaml"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, c.IntMustResolve("Value.Int"))
+	assert.Equal(t, 2, c.IntMustResolve("value.int"))
+	assert.True(t, c.BoolMustResolve("value.bool"))
+	assert.ElementsMatch(t, []Path{
+		"config",
+		"config.Value",
+		"config.Value.Int",
+		"config.Value.Bool",
+		"config.x",
+		"config.x.a",
+		"config.y",
+		"config.y.#",
+		"config.y.#0",
+		"config.z",
+	}, slices.Collect(maps.Keys(c.mapping)))
+	assert.Empty(t, c.ValueMustResolve("z"))
+	assert.Equal(t, []int{3}, c.IntsMustResolve("y"))
+
+	var diffs []Diff
+	c.Watch("", func(d Diff) {
+		diffs = append(diffs, d)
+	})
+	size := c.Size()
+	assert.True(t, c.Set("value.INT", "3"))
+	assert.Equal(t, 3, c.IntMustResolve("Value.Int"))
+	assert.Equal(t, size, c.Size())
+	assert.Equal(t, []Diff{{Changed: []Change{{Path: "config.Value.Int", Old: "2", New: "3"}}}}, diffs)
+}
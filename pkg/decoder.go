@@ -0,0 +1,190 @@
+package config
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/multiformats/go-multiaddr"
+	"github.com/timberio/go-datemath"
+)
+
+var (
+	decoders     = make(map[reflect.Type]func(string) (any, error))
+	decoderMutex sync.RWMutex
+)
+
+func init() {
+	for _, err := range []error{
+		RegisterDecoder(strconv.ParseBool),
+		RegisterDecoder(parseFloat),
+		RegisterDecoder(parseInt),
+		RegisterDecoder(parseInt64),
+		RegisterDecoder(parseString),
+		RegisterDecoder(time.ParseDuration),
+		RegisterDecoder(parseTime),
+		RegisterDecoder(url.Parse),
+		RegisterDecoder(multiaddr.NewMultiaddr),
+	} {
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterDecoder registers the decoder used for parsing configuration values as type T. Registering a decoder for a
+// type that is already registered replaces the existing decoder.
+//
+// The returned error will be non-nil if the decoder is nil.
+func RegisterDecoder[T any](decoder func(string) (T, error)) error {
+	t := reflect.TypeFor[T]()
+	if decoder == nil {
+		return fmt.Errorf("configuration: decoder cannot be nil for type %s", t)
+	}
+
+	decoderMutex.Lock()
+	defer decoderMutex.Unlock()
+	decoders[t] = func(v string) (any, error) {
+		return decoder(v)
+	}
+	return nil
+}
+
+// GetFrom retrieves the value for the provided path from the provided Config, parsed as type T using the decoder
+// registered for T. If no decoder is registered for T, the value is parsed using encoding.TextUnmarshaler if
+// implemented by T.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as type T
+func GetFrom[T any](c *Config, path string) (T, error) {
+	var t T
+	v, err := c.Value(path)
+	if err != nil {
+		return t, err
+	}
+
+	d, err := decode(reflect.TypeFor[T](), v)
+	if err != nil {
+		return t, &PathError{Err: err, Operation: "decode", Path: path}
+	}
+
+	// a decoder for an interface type may return a nil value, which is returned as the zero value of T
+	t, _ = d.(T)
+	return t, nil
+}
+
+// MustGetFrom is similar behavior to GetFrom, but panics if an error occurs.
+func MustGetFrom[T any](c *Config, path string) T {
+	v, err := GetFrom[T](c, path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Get retrieves the value for the provided path from the default configuration, parsed as type T.
+//
+// See GetFrom for how values are parsed.
+func Get[T any](path string) (T, error) {
+	c, err := instance()
+	if err != nil {
+		var t T
+		return t, err
+	}
+	return GetFrom[T](c, path)
+}
+
+// MustGet is similar behavior to Get, but panics if an error occurs.
+func MustGet[T any](path string) T {
+	v, err := Get[T](path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// decoder returns the decoder registered for the provided type.
+func decoder(t reflect.Type) (func(string) (any, error), bool) {
+	decoderMutex.RLock()
+	defer decoderMutex.RUnlock()
+	d, ok := decoders[t]
+	return d, ok
+}
+
+// decodable returns whether a decoder is registered for the provided type, or the type implements
+// encoding.TextUnmarshaler.
+func decodable(t reflect.Type) bool {
+	_, ok := decoder(t)
+	return ok || reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]())
+}
+
+// decode parses the provided value as the provided type using the registered decoder for the type, or
+// encoding.TextUnmarshaler if implemented by the type.
+func decode(t reflect.Type, v string) (any, error) {
+	if d, ok := decoder(t); ok {
+		return d(v)
+	}
+
+	if reflect.PointerTo(t).Implements(reflect.TypeFor[encoding.TextUnmarshaler]()) {
+		p := reflect.New(t)
+		if err := p.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(v)); err != nil {
+			return nil, err
+		}
+		return p.Elem().Interface(), nil
+	}
+	return nil, errors.New("no decoder registered for type " + t.String())
+}
+
+func parseFloat(v string) (float64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseFloat(v, 64)
+}
+
+func parseInt(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func parseInt64(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(v, 10, 64)
+}
+
+func parseString(v string) (string, error) {
+	return v, nil
+}
+
+// parseSizeBytes parses the provided value as a number of bytes using SI or IEC units (e.g. `5MB`, `512 KiB`).
+func parseSizeBytes(v string) (int64, error) {
+	if v == "" {
+		return 0, nil
+	}
+
+	s, err := humanize.ParseBytes(v)
+	if err != nil {
+		return 0, err
+	}
+	return int64(s), nil
+}
+
+// parseTime parses the provided value as a time.Time using date math expressions (e.g. `now-1h`) or absolute times.
+func parseTime(v string) (time.Time, error) {
+	expr, err := datemath.Parse(v)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return expr.Time(), nil
+}
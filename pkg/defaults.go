@@ -0,0 +1,107 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// defaultsName defines the name of the source for default values in the LoadReport.
+const defaultsName = "defaults"
+
+var (
+	defaults      []defaultValue
+	defaultsMutex sync.RWMutex
+)
+
+// defaultValue is a container for the default value for a configuration path.
+type defaultValue struct {
+	path  Path
+	value any
+}
+
+// SetDefault registers the default value for the provided path. Default values are the lowest-precedence layer of the
+// configuration, and are replaced by the values for the same path from any other source. Unlike Set, which replaces
+// the current value of a loaded configuration, default values are applied each time a configuration is loaded or
+// reloaded, and should be registered before calling Load or New.
+//
+// Paths are resolved relative to the root path of the configuration, for example, both `server.port` and
+// `config.server.port` map to the path `config.server.port`. The value can be a scalar, slice, or map, as well as a
+// time.Duration or a type implementing encoding.TextMarshaler (e.g. time.Time). Registering a default value for a path
+// replaces any default value previously registered for the path.
+//
+// The returned error will be non-nil if the path is empty or the value is not supported.
+func SetDefault(path string, value any) error {
+	d, err := newDefaultValue(path, value)
+	if err != nil {
+		return err
+	}
+
+	defaultsMutex.Lock()
+	defer defaultsMutex.Unlock()
+	defaults = append(slices.DeleteFunc(defaults, func(v defaultValue) bool {
+		return v.path.Equals(d.path)
+	}), d)
+	return nil
+}
+
+// newDefaultValue creates a new defaultValue for the provided path and value.
+func newDefaultValue(path string, value any) (defaultValue, error) {
+	p := Path(strings.TrimSpace(path))
+	if p.Empty() {
+		return defaultValue{}, errors.New("configuration: default value path cannot be empty")
+	}
+
+	if _, err := newConfigMap(map[string]any{p.String(): value}); err != nil {
+		return defaultValue{}, err
+	}
+	return defaultValue{path: p, value: value}, nil
+}
+
+// defaults returns the source for the default values registered using SetDefault, or nil if no default values were
+// registered.
+func (s *store) defaults() []layer {
+	defaultsMutex.RLock()
+	values := slices.Clone(defaults)
+	defaultsMutex.RUnlock()
+
+	if len(values) == 0 {
+		return nil
+	}
+	return []layer{{source: newDefaultsSource(values, s.namespace)}}
+}
+
+// newDefaultsSource creates a new source for the provided default values, with each path resolved relative to the
+// provided root Path.
+func newDefaultsSource(values []defaultValue, root Path) source {
+	return source{
+		name: defaultsName,
+		read: func() (map[string]any, error) {
+			rawConfig := make(map[string]any)
+			for _, d := range values {
+				putNested(rawConfig, resolve(root, d.path), d.value)
+			}
+			return rawConfig, nil
+		},
+	}
+}
+
+// putNested sets the provided value for the provided path in the provided nested map, replacing any values for the
+// path or its parent paths that are not maps. The maps for the parent paths are copied, so that maps provided as values
+// are not modified.
+func putNested(m map[string]any, path Path, value any) {
+	elements := strings.Split(path.String(), ".")
+	for _, e := range elements[:len(elements)-1] {
+		child, ok := m[e].(map[string]any)
+		if ok {
+			child = maps.Clone(child)
+		} else {
+			child = make(map[string]any)
+		}
+		m[e] = child
+		m = child
+	}
+	m[elements[len(elements)-1]] = value
+}
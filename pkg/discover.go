@@ -0,0 +1,83 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Enumeration of modes for discovering configuration files in the search paths.
+const (
+	// SearchFirst uses the configuration file in the search path with the highest precedence.
+	SearchFirst SearchMode = iota
+
+	// SearchAll merges the configuration files in all search paths, with the files in search paths with a higher
+	// precedence taking precedence over the files before them.
+	SearchAll
+)
+
+// SearchMode defines the type for modes of discovering configuration files in the search paths.
+type SearchMode int
+
+// discover returns the configuration files found in the search paths using the SearchMode, in order of increasing
+// precedence.
+func (o *Option) discover() []configFile {
+	fileName := o.fileName
+	if fileName == "" {
+		fileName = defaultFilePath
+	}
+
+	var files []configFile
+	for _, dir := range o.searchPaths {
+		filePath := filepath.Join(dir, fileName)
+		if info, err := os.Stat(filePath); err != nil || info.IsDir() {
+			continue
+		}
+
+		files = append(files, configFile{path: filePath})
+		if o.searchMode == SearchFirst {
+			break
+		}
+	}
+	slices.Reverse(files)
+	return files
+}
+
+// defaultSearchPaths returns the default search paths for the application with the provided name, in order of
+// decreasing precedence.
+func defaultSearchPaths(app string) []string {
+	var dirs []string
+	if wd, err := os.Getwd(); err == nil {
+		for dir := wd; ; dir = filepath.Dir(dir) {
+			dirs = append(dirs, dir)
+			if isProjectRoot(dir) || filepath.Dir(dir) == dir {
+				break
+			}
+		}
+
+		if !isProjectRoot(dirs[len(dirs)-1]) {
+			dirs = dirs[:1]
+		}
+	}
+
+	if app == "" {
+		return dirs
+	}
+
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		dirs = append(dirs, filepath.Join(configHome, app))
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config", app))
+	}
+	return append(dirs, filepath.Join("/etc", app))
+}
+
+// isProjectRoot returns whether the provided directory contains a `go.mod` file or `.git` directory.
+func isProjectRoot(dir string) bool {
+	for _, name := range []string{"go.mod", ".git"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// dropIns returns the layers for the configuration files in the provided drop-in directories, in order of increasing
+// precedence. Files within each directory are ordered lexically, and only files with a registered format are
+// included. Hidden files, sub-directories, and directories that do not exist are skipped.
+func dropIns(dirs []string) ([]layer, error) {
+	var layers []layer
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			return nil, fmt.Errorf("configuration: could not read drop-in directory %s: %w", dir, err)
+		}
+
+		for _, e := range entries {
+			if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+				continue
+			}
+
+			if _, ok := format(filepath.Ext(e.Name())); ok {
+				layers = append(layers, layer{source: fileSource(filepath.Join(dir, e.Name())), required: true})
+			}
+		}
+	}
+	return layers, nil
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// The default format string for collection element indexes in environment variable names.
+	defaultEnvIndexFormat = `%d`
+
+	// The default separator for path elements in environment variable names.
+	defaultEnvSeparator = `_`
+)
+
+// envNameInvalidChars matches characters that are not permitted in environment variable names.
+var envNameInvalidChars = regexp.MustCompile(`[^A-Z0-9_]`)
+
+// envSource is the implementation of Source for environment variable overrides.
+type envSource struct {
+	opts   *Option
+	prefix string
+}
+
+// Name returns the name of the envSource.
+func (e envSource) Name() string {
+	if e.prefix != "" {
+		return "env:" + e.prefix
+	}
+	return "env"
+}
+
+// Read returns no configuration values, since environment variables only override the values for the paths defined
+// by the sources before the envSource.
+func (e envSource) Read() (map[string]any, error) {
+	return map[string]any{}, nil
+}
+
+// override replaces the value for each path in the provided mapping with the value of the corresponding environment
+// variable, if set. Paths that have sub-paths and collection lengths are not overridden, nor are paths whose
+// environment variable name is reserved for selecting the active profiles (e.g. `config.profiles` without a prefix).
+func (e envSource) override(mapping configMap) error {
+	parents := mapping.parents()
+	for k := range mapping {
+		if _, ok := parents[k]; ok || strings.HasSuffix(k.String(), ".#") {
+			continue
+		}
+
+		name := envName(k, e.prefix, e.opts)
+		if name == profilesEnvVar {
+			continue
+		}
+
+		if v, ok := os.LookupEnv(name); ok {
+			mapping[k] = v
+		}
+	}
+	return nil
+}
+
+// envName returns the environment variable name for the provided path using the provided prefix.
+func envName(path Path, prefix string, opts *Option) string {
+	separator := opts.envSeparator
+	if separator == "" {
+		separator = defaultEnvSeparator
+	}
+
+	indexFormat := opts.envIndexFormat
+	if indexFormat == "" {
+		indexFormat = defaultEnvIndexFormat
+	}
+
+	var elements []string
+	if prefix != "" {
+		elements = append(elements, prefix)
+	}
+
+	for _, e := range strings.Split(path.String(), ".") {
+		if i, err := strconv.Atoi(strings.TrimPrefix(e, "#")); err == nil && strings.HasPrefix(e, "#") {
+			e = fmt.Sprintf(indexFormat, i)
+		}
+		elements = append(elements, envNameInvalidChars.ReplaceAllString(strings.ToUpper(e), "_"))
+	}
+	return strings.Join(elements, separator)
+}
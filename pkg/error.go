@@ -7,6 +7,7 @@ import (
 
 // Enumeration of errors that may be returned by configuration operations.
 const (
+	ErrAlreadyLoaded  = configErr("already loaded")
 	ErrNotInitialized = configErr("not initialized")
 	ErrPathNotFound   = configErr("path not found")
 )
@@ -49,3 +50,31 @@ func (e *PathError) Error() string {
 func (e *PathError) Unwrap() error {
 	return e.Err
 }
+
+// LoadError is used for recording errors that occur when reading configuration sources.
+type LoadError struct {
+	Report LoadReport
+}
+
+// Error returns the error message for the LoadError.
+func (e *LoadError) Error() string {
+	var le strings.Builder
+	le.WriteString("configuration: could not read sources")
+	for _, s := range e.Report.Sources {
+		if s.Status == SourceFailed {
+			le.WriteString("; " + s.Name + ": " + s.Err.Error())
+		}
+	}
+	return le.String()
+}
+
+// Unwrap returns the errors for each configuration source that could not be read.
+func (e *LoadError) Unwrap() []error {
+	var errs []error
+	for _, s := range e.Report.Sources {
+		if s.Status == SourceFailed {
+			errs = append(errs, s.Err)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// The command-line argument used for overriding configuration values.
+const setArg = `set`
+
+// Overrides is a flag.Value that collects `path=value` configuration overrides, for example from repeated `--set`
+// flags. Overrides defined on a flag.FlagSet are applied when the flag.FlagSet is provided using WithFlagSet.
+type Overrides []string
+
+// Set implements flag.Value for Overrides.
+func (o *Overrides) Set(value string) error {
+	if _, _, ok := strings.Cut(value, "="); !ok {
+		return fmt.Errorf("configuration: invalid override %q, expected path=value", value)
+	}
+	*o = append(*o, value)
+	return nil
+}
+
+// String implements flag.Value for Overrides.
+func (o *Overrides) String() string {
+	if o == nil {
+		return ""
+	}
+	return strings.Join(*o, ",")
+}
+
+// override is a container for a configuration value override.
+type override struct {
+	path  Path
+	value string
+}
+
+// flagSource is the implementation of Source for command-line overrides.
+type flagSource struct {
+	name      string
+	namespace Path
+	overrides func() ([]override, error)
+	strict    bool
+}
+
+// newArgsSource creates a new flagSource for the `--set path=value` overrides in the provided command-line arguments.
+func newArgsSource(args []string, o *Option) flagSource {
+	return flagSource{
+		name:      "args",
+		namespace: o.namespace(),
+		overrides: func() ([]override, error) {
+			return parseArgs(args)
+		},
+		strict: o.strict,
+	}
+}
+
+// newFlagSetSource creates a new flagSource for the flags set on the provided parsed flag.FlagSet.
+func newFlagSetSource(flagSet *flag.FlagSet, o *Option) flagSource {
+	return flagSource{
+		name:      "flags:" + flagSet.Name(),
+		namespace: o.namespace(),
+		overrides: func() ([]override, error) {
+			var overrides []override
+			flagSet.Visit(func(f *flag.Flag) {
+				if o, ok := f.Value.(*Overrides); ok {
+					for _, v := range *o {
+						p, v, _ := strings.Cut(v, "=")
+						overrides = append(overrides, override{path: Path(p), value: v})
+					}
+					return
+				}
+				overrides = append(overrides, override{path: Path(f.Name), value: f.Value.String()})
+			})
+			return overrides, nil
+		},
+		strict: o.strict,
+	}
+}
+
+// Name returns the name of the flagSource.
+func (f flagSource) Name() string {
+	return f.name
+}
+
+// Read returns the configuration values for the command-line overrides.
+func (f flagSource) Read() (map[string]any, error) {
+	overrides, err := f.overrides()
+	if err != nil {
+		return nil, err
+	}
+
+	rawConfig := make(map[string]any)
+	for _, o := range overrides {
+		putNested(rawConfig, resolve(f.namespace, o.path), o.value)
+	}
+	return rawConfig, nil
+}
+
+// override replaces the values in the provided mapping with the command-line overrides. If the flagSource is strict,
+// the returned error will be non-nil if an override does not match a path in the provided mapping.
+func (f flagSource) override(mapping configMap) error {
+	overrides, err := f.overrides()
+	if err != nil {
+		return err
+	}
+
+	for _, o := range overrides {
+		key := resolve(f.namespace, o.path)
+		if k, ok := mapping.lookup(key); ok {
+			mapping[k] = o.value
+			continue
+		}
+
+		if f.strict {
+			return &PathError{Err: ErrPathNotFound, Operation: "override", Path: o.path.String()}
+		}
+		mapping[key] = o.value
+	}
+	return nil
+}
+
+// parseArgs parses the `--set path=value` overrides from the provided command-line arguments. Arguments after the
+// `--` terminator are positional arguments, and are not parsed.
+func parseArgs(args []string) ([]override, error) {
+	var overrides []override
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--" {
+			break
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if !strings.HasPrefix(args[i], "-") || name != setArg {
+			continue
+		}
+
+		if !hasValue {
+			if i++; i >= len(args) {
+				return nil, errors.New("configuration: missing value for argument --" + setArg)
+			}
+			value = args[i]
+		}
+
+		p, v, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(p) == "" {
+			return nil, fmt.Errorf("configuration: invalid override %q, expected path=value", value)
+		}
+		overrides = append(overrides, override{path: Path(p), value: v})
+	}
+	return overrides, nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	formats     = make(map[string]FormatDecoder)
+	formatMutex sync.RWMutex
+
+	// tomlLocalLayouts maps the names of the placeholder time zones used by the TOML decoder for local date-times,
+	// dates, and times to the layout of each.
+	tomlLocalLayouts = map[string]string{
+		"datetime-local": "2006-01-02T15:04:05.999999999",
+		"date-local":     time.DateOnly,
+		"time-local":     "15:04:05.999999999",
+	}
+)
+
+func init() {
+	for ext, decoder := range map[string]FormatDecoder{
+		".json": readJson,
+		".toml": readToml,
+		".yaml": readYaml,
+		".yml":  readYaml,
+	} {
+		if err := RegisterFormat(ext, decoder); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// FormatDecoder decodes the raw content of a configuration file into a nested mapping of configuration values.
+type FormatDecoder func([]byte) (map[string]any, error)
+
+// RegisterFormat registers the FormatDecoder used for reading configuration files with the provided file extension
+// (e.g. `.hcl`). Registering a decoder for an extension that is already registered replaces the existing decoder.
+//
+// The returned error will be non-nil if the extension is empty or the decoder is nil.
+func RegisterFormat(ext string, decoder FormatDecoder) error {
+	ext = normalizeExtension(ext)
+	if ext == "" {
+		return errors.New("configuration: file extension cannot be empty")
+	}
+
+	if decoder == nil {
+		return fmt.Errorf("configuration: decoder cannot be nil for file extension %s", ext)
+	}
+
+	formatMutex.Lock()
+	defer formatMutex.Unlock()
+	formats[ext] = decoder
+	return nil
+}
+
+// format returns the FormatDecoder registered for the provided file extension.
+func format(ext string) (FormatDecoder, bool) {
+	formatMutex.RLock()
+	defer formatMutex.RUnlock()
+	decoder, ok := formats[normalizeExtension(ext)]
+	return decoder, ok
+}
+
+// formatExtensions returns the sorted list of registered file extensions.
+func formatExtensions() []string {
+	formatMutex.RLock()
+	defer formatMutex.RUnlock()
+
+	extensions := make([]string, 0, len(formats))
+	for ext := range formats {
+		extensions = append(extensions, ext)
+	}
+	slices.Sort(extensions)
+	return extensions
+}
+
+func normalizeExtension(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+func readJson(b []byte) (map[string]any, error) {
+	var jsonConfig map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&jsonConfig); err != nil {
+		return nil, fmt.Errorf("configuration: could not read JSON Configuration: %w", err)
+	}
+
+	// the content must contain a single JSON value, so that truncated or concatenated content is not accepted
+	if err := decoder.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return nil, errors.New("configuration: could not read JSON Configuration: unexpected data after top-level value")
+	}
+	return jsonConfig, nil
+}
+
+func readToml(bytes []byte) (map[string]any, error) {
+	var tomlConfig map[string]any
+	if err := toml.Unmarshal(bytes, &tomlConfig); err != nil {
+		return nil, fmt.Errorf("configuration: could not read TOML Configuration: %w", err)
+	}
+	return formatTomlLocalTimes(tomlConfig).(map[string]any), nil
+}
+
+// formatTomlLocalTimes replaces the TOML local date-times, dates, and times in the provided decoded value with their
+// original layout, since they are decoded as time.Time values using a placeholder time zone.
+func formatTomlLocalTimes(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			v[k] = formatTomlLocalTimes(e)
+		}
+	case []map[string]any:
+		for _, e := range v {
+			formatTomlLocalTimes(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = formatTomlLocalTimes(e)
+		}
+	case time.Time:
+		if layout, ok := tomlLocalLayouts[v.Location().String()]; ok {
+			return v.Format(layout)
+		}
+	}
+	return v
+}
+
+func readYaml(bytes []byte) (map[string]any, error) {
+	var yamlConfig map[string]any
+	if err := yaml.Unmarshal(bytes, &yamlConfig); err != nil {
+		return nil, fmt.Errorf("configuration: could not read YAML Configuration: %w", err)
+	}
+	return yamlConfig, nil
+}
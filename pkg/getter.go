@@ -0,0 +1,223 @@
+package config
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/multiformats/go-multiaddr"
+)
+
+// Bool retrieves the boolean value for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a boolean
+func (c *Config) Bool(path string) (bool, error) {
+	return GetFrom[bool](c, path)
+}
+
+// BoolMustResolve is similar behavior to Bool, but panics if an error occurs.
+func (c *Config) BoolMustResolve(path string) bool {
+	return MustGetFrom[bool](c, path)
+}
+
+// Bool retrieves the boolean value for the provided path from the default configuration.
+func Bool(path string) (bool, error) {
+	return Get[bool](path)
+}
+
+// BoolMustResolve is similar behavior to Bool, but panics if an error occurs.
+func BoolMustResolve(path string) bool {
+	return MustGet[bool](path)
+}
+
+// Duration retrieves the time.Duration value for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a Duration
+func (c *Config) Duration(path string) (time.Duration, error) {
+	return GetFrom[time.Duration](c, path)
+}
+
+// DurationMustResolve is similar behavior to Duration, but panics if an error occurs.
+func (c *Config) DurationMustResolve(path string) time.Duration {
+	return MustGetFrom[time.Duration](c, path)
+}
+
+// Duration retrieves the time.Duration value for the provided path from the default configuration.
+func Duration(path string) (time.Duration, error) {
+	return Get[time.Duration](path)
+}
+
+// DurationMustResolve is similar behavior to Duration, but panics if an error occurs.
+func DurationMustResolve(path string) time.Duration {
+	return MustGet[time.Duration](path)
+}
+
+// Float retrieves the float value for the provided path.
+//
+// The returned error will be non-nil and the returned float value will be set to 0 if the value corresponding to the
+// provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a float
+func (c *Config) Float(path string) (float64, error) {
+	return GetFrom[float64](c, path)
+}
+
+// FloatMustResolve is similar behavior to Float, but panics if an error occurs.
+func (c *Config) FloatMustResolve(path string) float64 {
+	return MustGetFrom[float64](c, path)
+}
+
+// Float retrieves the float value for the provided path from the default configuration.
+func Float(path string) (float64, error) {
+	return Get[float64](path)
+}
+
+// FloatMustResolve is similar behavior to Float, but panics if an error occurs.
+func FloatMustResolve(path string) float64 {
+	return MustGet[float64](path)
+}
+
+// Int retrieves the integer value for the provided path.
+//
+// The returned error will be non-nil and the returned integer value will be set to 0 if the value corresponding to the
+// provided path:
+//   - could not be found
+//   - was found, but could not be parsed as an integer
+func (c *Config) Int(path string) (int, error) {
+	return GetFrom[int](c, path)
+}
+
+// IntMustResolve is similar behavior to Int, but panics if an error occurs.
+func (c *Config) IntMustResolve(path string) int {
+	return MustGetFrom[int](c, path)
+}
+
+// Int retrieves the integer value for the provided path from the default configuration.
+func Int(path string) (int, error) {
+	return Get[int](path)
+}
+
+// IntMustResolve is similar behavior to Int, but panics if an error occurs.
+func IntMustResolve(path string) int {
+	return MustGet[int](path)
+}
+
+// Multiaddr retrieves the multiaddr.Multiaddr value for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a multiaddr.Multiaddr value
+func (c *Config) Multiaddr(path string) (multiaddr.Multiaddr, error) {
+	return GetFrom[multiaddr.Multiaddr](c, path)
+}
+
+// MultiaddrMustResolve is similar in behavior to Multiaddr, but panics if an error occurs.
+func (c *Config) MultiaddrMustResolve(path string) multiaddr.Multiaddr {
+	return MustGetFrom[multiaddr.Multiaddr](c, path)
+}
+
+// Multiaddr retrieves the multiaddr.Multiaddr value for the provided path from the default configuration.
+func Multiaddr(path string) (multiaddr.Multiaddr, error) {
+	return Get[multiaddr.Multiaddr](path)
+}
+
+// MultiaddrMustResolve is similar in behavior to Multiaddr, but panics if an error occurs.
+func MultiaddrMustResolve(path string) multiaddr.Multiaddr {
+	return MustGet[multiaddr.Multiaddr](path)
+}
+
+// SizeBytes retrieves the value representing a unit value of bytes for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a byte size value
+func (c *Config) SizeBytes(path string) (int64, error) {
+	v, err := c.Value(path)
+	if err != nil {
+		return 0, err
+	}
+
+	s, err := parseSizeBytes(v)
+	if err != nil {
+		return 0, &PathError{Err: err, Operation: "decode", Path: path}
+	}
+	return s, nil
+}
+
+// SizeBytesMustResolve is similar behavior to SizeBytes, but panics if an error occurs.
+func (c *Config) SizeBytesMustResolve(path string) int64 {
+	v, err := c.SizeBytes(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// SizeBytes retrieves the value representing a unit value of bytes for the provided path from the default configuration.
+func SizeBytes(path string) (int64, error) {
+	c, err := instance()
+	if err != nil {
+		return 0, err
+	}
+	return c.SizeBytes(path)
+}
+
+// SizeBytesMustResolve is similar behavior to SizeBytes, but panics if an error occurs.
+func SizeBytesMustResolve(path string) int64 {
+	v, err := SizeBytes(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Time retrieves the time.Time value for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a time.Time value
+func (c *Config) Time(path string) (time.Time, error) {
+	return GetFrom[time.Time](c, path)
+}
+
+// TimeMustResolve is similar behavior to Time, but panics if an error occurs.
+func (c *Config) TimeMustResolve(path string) time.Time {
+	return MustGetFrom[time.Time](c, path)
+}
+
+// Time retrieves the time.Time value for the provided path from the default configuration.
+func Time(path string) (time.Time, error) {
+	return Get[time.Time](path)
+}
+
+// TimeMustResolve is similar behavior to Time, but panics if an error occurs.
+func TimeMustResolve(path string) time.Time {
+	return MustGet[time.Time](path)
+}
+
+// URL retrieves the url.URL value for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but could not be parsed as a url.URL value
+func (c *Config) URL(path string) (*url.URL, error) {
+	return GetFrom[*url.URL](c, path)
+}
+
+// URLMustResolve is similar in behavior to URL, but panics if an error occurs.
+func (c *Config) URLMustResolve(path string) *url.URL {
+	return MustGetFrom[*url.URL](c, path)
+}
+
+// URL retrieves the url.URL value for the provided path from the default configuration.
+func URL(path string) (*url.URL, error) {
+	return Get[*url.URL](path)
+}
+
+// URLMustResolve is similar in behavior to URL, but panics if an error occurs.
+func URLMustResolve(path string) *url.URL {
+	return MustGet[*url.URL](path)
+}
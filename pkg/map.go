@@ -1,15 +1,25 @@
 package config
 
 import (
+	"cmp"
+	"encoding"
 	"fmt"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/transientvariable/anchor"
 )
 
-// formatSliceSuffix defines the format string for configuration paths that map to elements of a slice.
-const formatSliceSuffix = "%s.#"
+const (
+	// formatSliceSuffix defines the format string for configuration paths that map to the length of a slice.
+	formatSliceSuffix = "%s.#"
+
+	// formatSliceElement defines the format string for configuration paths that map to elements of a slice.
+	formatSliceElement = "%s.#%d"
+)
 
 // configMap represents a map that uses the string type for both keys and values.
 type configMap map[Path]string
@@ -38,21 +48,28 @@ func (m configMap) contains(key string) bool {
 	return false
 }
 
-// delete deletes a key out of the map with the given prefix.
+// delete deletes the given key, and any keys with the given key as a prefix, out of the map, ignoring case.
 func (m configMap) delete(prefix Path) {
+	p := strings.ToLower(prefix.String())
 	for k := range m {
-		match := k == prefix
-		if !match {
-			if !strings.HasPrefix(k.String(), prefix.String()) {
-				continue
-			}
+		if s := strings.ToLower(k.String()); s == p || strings.HasPrefix(s, p+".") {
+			delete(m, k)
+		}
+	}
+}
 
-			if k[len(prefix):len(prefix)+1] != "." {
-				continue
-			}
+// lookup returns the key in the map that matches the provided key, ignoring case.
+func (m configMap) lookup(key Path) (Path, bool) {
+	if _, ok := m[key]; ok {
+		return key, true
+	}
+
+	for k := range m {
+		if k.Equals(key) {
+			return k, true
 		}
-		delete(m, k)
 	}
+	return "", false
 }
 
 // keys returns all the top-level keys in this map
@@ -73,16 +90,111 @@ func (m configMap) keys() []Path {
 	return result
 }
 
-// merge merges the contents of the other FlatMapStr into this one.
-func (m configMap) merge(source configMap) {
-	for _, prefix := range source.keys() {
-		m.delete(prefix)
-		for k, v := range source {
-			if strings.HasPrefix(k.String(), prefix.String()) {
-				m[k] = v
+// parents returns the set of paths in the map that have sub-paths.
+func (m configMap) parents() map[Path]struct{} {
+	parents := make(map[Path]struct{})
+	for k := range m {
+		for p := k.Parent(); !p.Empty(); p = p.Parent() {
+			parents[p] = struct{}{}
+		}
+	}
+	return parents
+}
+
+// merge merges the contents of the source configMap into this one, with the values of the source taking precedence:
+//   - scalar values replace the value for the same path, including any sub-paths
+//   - maps are merged recursively, retaining paths that are not present in the source
+//   - collections (e.g. slices) replace the entire collection for the same path
+//
+// Paths are matched ignoring case, and the existing case of matching paths is retained. If the value for a path changes
+// between a scalar, map, or collection, the existing value and its sub-paths are replaced. The provided empty maps are
+// the paths of the empty maps in the source (see emptyMaps), which are merged as maps rather than scalar values.
+func (m configMap) merge(source configMap, empty map[Path]struct{}) {
+	keys := make([]Path, 0, len(source))
+	for k := range source {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b Path) int {
+		return cmp.Or(cmp.Compare(a.Depth(), b.Depth()), cmp.Compare(a, b))
+	})
+
+	sourceParents := source.parents()
+	parents := m.parents()
+	targets := make(map[Path]Path, len(keys))
+	for _, k := range keys {
+		target := k
+		if parent, ok := targets[k.Parent()]; ok {
+			target = parent.Join(Path(k.Base()))
+		}
+
+		if existing, ok := m.lookup(target); ok {
+			target = existing
+			switch {
+			case source.isSlice(k):
+				m.delete(target)
+			case isParent(sourceParents, k) || isParent(empty, k):
+				if m.isSlice(target) || !isParent(parents, target) {
+					m.delete(target)
+				}
+			default:
+				m.delete(target)
 			}
 		}
+
+		targets[k] = target
+		m[target] = source[k]
+	}
+}
+
+// emptyMaps returns the paths of the empty maps in the provided source, which are otherwise indistinguishable from
+// empty scalar values once flattened.
+func emptyMaps(source any) map[Path]struct{} {
+	empty := make(map[Path]struct{})
+	value := reflect.ValueOf(source)
+	if value.Kind() == reflect.Map {
+		for _, key := range value.MapKeys() {
+			findEmptyMaps(key.String(), value.MapIndex(key), empty)
+		}
 	}
+	return empty
+}
+
+func findEmptyMaps(path string, value reflect.Value, empty map[Path]struct{}) {
+	if value.Kind() == reflect.Interface {
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Len() == 0 {
+			empty[Path(path)] = struct{}{}
+		}
+
+		for _, k := range value.MapKeys() {
+			if k.Kind() == reflect.Interface {
+				k = k.Elem()
+			}
+
+			if k.Kind() == reflect.String {
+				findEmptyMaps(fmt.Sprintf("%s.%s", path, k.String()), value.MapIndex(k), empty)
+			}
+		}
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			findEmptyMaps(fmt.Sprintf(formatSliceElement, path, i), value.Index(i), empty)
+		}
+	}
+}
+
+// isSlice returns whether the given key maps to a collection (e.g. slice).
+func (m configMap) isSlice(key Path) bool {
+	_, ok := m[Path(fmt.Sprintf(formatSliceSuffix, key))]
+	return ok
+}
+
+func isParent(parents map[Path]struct{}, key Path) bool {
+	_, ok := parents[key]
+	return ok
 }
 
 func flatten(path string, value reflect.Value, data map[Path]string) error {
@@ -92,6 +204,9 @@ func flatten(path string, value reflect.Value, data map[Path]string) error {
 
 	var reflectedValue string
 	switch value.Kind() {
+	case reflect.Invalid:
+		// null values (e.g. YAML `~` or JSON `null`) are represented as an empty string
+		break
 	case reflect.Bool:
 		if value.Bool() {
 			reflectedValue = "true"
@@ -99,8 +214,15 @@ func flatten(path string, value reflect.Value, data map[Path]string) error {
 			reflectedValue = "false"
 		}
 		break
-	case reflect.Int:
-		reflectedValue = fmt.Sprintf("%d", value.Int())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if value.Type() == reflect.TypeOf(time.Duration(0)) {
+			reflectedValue = time.Duration(value.Int()).String()
+			break
+		}
+		reflectedValue = strconv.FormatInt(value.Int(), 10)
+		break
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		reflectedValue = strconv.FormatUint(value.Uint(), 10)
 		break
 	case reflect.Map:
 		if err := flattenMap(path, value, data); err != nil {
@@ -115,8 +237,21 @@ func flatten(path string, value reflect.Value, data map[Path]string) error {
 	case reflect.String:
 		reflectedValue = value.String()
 		break
+	case reflect.Struct:
+		// structured values such as time.Time (e.g. TOML datetimes) are represented by their text encoding
+		m, ok := value.Interface().(encoding.TextMarshaler)
+		if !ok {
+			return fmt.Errorf("unknown value type [%s] for path [%s]\nusing data: %s\n", value, path, anchor.ToJSONFormatted(data))
+		}
+
+		b, err := m.MarshalText()
+		if err != nil {
+			return err
+		}
+		reflectedValue = string(b)
+		break
 	case reflect.Float32, reflect.Float64:
-		reflectedValue = value.String()
+		reflectedValue = strconv.FormatFloat(value.Float(), 'f', -1, value.Type().Bits())
 		break
 	default:
 		return fmt.Errorf("unknown value type [%s] for path [%s]\nusing data: %s\n", value, path, anchor.ToJSONFormatted(data))
@@ -144,10 +279,9 @@ func flattenMap(path string, value reflect.Value, config map[Path]string) error
 }
 
 func flattenSlice(path string, value reflect.Value, config map[Path]string) error {
-	path = fmt.Sprintf(formatSliceSuffix, path)
-	config[Path(path)] = fmt.Sprintf("%d", value.Len())
+	config[Path(fmt.Sprintf(formatSliceSuffix, path))] = fmt.Sprintf("%d", value.Len())
 	for i := 0; i < value.Len(); i++ {
-		if err := flatten(fmt.Sprintf("%s%d", path, i), value.Index(i), config); err != nil {
+		if err := flatten(fmt.Sprintf(formatSliceElement, path, i), value.Index(i), config); err != nil {
 			return err
 		}
 	}
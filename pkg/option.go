@@ -1,16 +1,380 @@
 package config
 
-import "strings"
+import (
+	"flag"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+	"strings"
+)
 
 // Option is a container for optional properties that can be used for initializing the configuration.
 type Option struct {
-	filePath string
+	envIndexFormat string
+	envSeparator   string
+	fileName       string
+	profiles       []string
+	provided       bool
+	root           string
+	rootless       bool
+	searchMode     SearchMode
+	searchPaths    []string
+	sources        []layerFunc
+	strict         bool
 }
 
-// WithFilePath sets the file path Option for the configuration. If the file path is not provided, the root of the
-// project directory will be used for loading the configuration.
+// WithArgs adds the command-line arguments Option for the configuration. Each `--set path=value` (or
+// `--set=path=value`) argument overrides the configuration value for the path, taking precedence over the sources
+// provided before the Option. Arguments other than `--set` are ignored, as are all arguments after the `--`
+// terminator.
+func WithArgs(args []string) func(*Option) {
+	args = slices.Clone(args)
+	return withOverlay(func(o *Option) overlay {
+		return newArgsSource(args, o)
+	})
+}
+
+// WithBytes adds the provided configuration content in the provided format (e.g. `yaml`, `.json`) to the Option for
+// the configuration.
+//
+// Configuration sources, including content provided using WithBytes, WithReader, and WithFS, files, and sources
+// provided using WithSource, are merged in the order the options are provided, with each source taking precedence
+// over the sources before it. If any source is provided, the default file path is not loaded.
+func WithBytes(b []byte, format string) func(*Option) {
+	return withSource(newBytesSource(b, format), true)
+}
+
+// WithEnvOverrides enables overriding any configuration value using environment variables. The environment variable
+// name for a path is the provided prefix followed by each element of the path in upper case, joined by the separator
+// (`_` by default). For example, `CONFIG_VALUE_INT=5` overrides `config.value.int` if the prefix is empty, and
+// `APP_CONFIG_VALUE_INT=5` overrides it if the prefix is `APP`. Characters that are not letters, digits, or
+// underscores are replaced with underscores.
+//
+// Environment variables only override the values for the paths defined by the sources provided before the Option, and
+// take precedence over them. Environment variable overrides are not interpolated. The environment variable
+// `CONFIG_PROFILES` is reserved for selecting the active profiles (see WithProfiles), so the path it would override
+// (`config.profiles` if the prefix is empty) can only be overridden using a non-empty prefix.
+func WithEnvOverrides(prefix string) func(*Option) {
+	prefix = strings.TrimSpace(prefix)
+	return withOverlay(func(o *Option) overlay {
+		return envSource{opts: o, prefix: prefix}
+	})
+}
+
+// WithEnvSeparator sets the separator used for joining path elements in environment variable names when environment
+// variable overrides are enabled using WithEnvOverrides. If the separator is not provided, `_` will be used.
+func WithEnvSeparator(separator string) func(*Option) {
+	return func(o *Option) {
+		o.envSeparator = separator
+	}
+}
+
+// WithDefaults adds the provided default values to the Option for the configuration. Each key of the provided map is a
+// path, resolved relative to the root path of the configuration. Like other sources, the default values take
+// precedence over the sources provided before the Option and the default values registered using SetDefault, so they
+// are typically provided first. Loading the configuration fails if a value is not supported.
+//
+// See SetDefault for how default values are applied.
+func WithDefaults(values map[string]any) func(*Option) {
+	var d []defaultValue
+	for _, path := range slices.Sorted(maps.Keys(values)) {
+		if p := Path(strings.TrimSpace(path)); !p.Empty() {
+			d = append(d, defaultValue{path: p, value: values[path]})
+		}
+	}
+
+	return func(o *Option) {
+		if len(d) > 0 {
+			o.sources = append(o.sources, func(o *Option, _ []string) ([]layer, error) {
+				return []layer{{source: newDefaultsSource(d, o.namespace())}}, nil
+			})
+		}
+	}
+}
+
+// WithDefaultSearchPaths adds the default search paths for the application with the provided name to the Option for
+// the configuration, in order of decreasing precedence:
+//   - the working directory
+//   - each parent directory of the working directory, up to the project root (the first directory containing a
+//     `go.mod` file or `.git` directory)
+//   - `$XDG_CONFIG_HOME/<app>`, or `$HOME/.config/<app>` if `XDG_CONFIG_HOME` is not set
+//   - `/etc/<app>`
+//
+// See WithSearchPaths for how configuration files are discovered.
+func WithDefaultSearchPaths(app string) func(*Option) {
+	return withSearchPaths(defaultSearchPaths(strings.TrimSpace(app)))
+}
+
+// WithDropInDirs adds the provided drop-in directories (e.g. `application.d`) to the Option for the configuration.
+// Every file in a drop-in directory with a registered format (see RegisterFormat) is merged onto the sources provided
+// before the Option in lexical order, allowing configuration fragments to be managed independently. Drop-in
+// directories that do not exist are skipped. Fragments added to or removed from a drop-in directory are read on the
+// next reload, and are detected by WatchFile.
+func WithDropInDirs(dirs ...string) func(*Option) {
+	var trimmed []string
+	for _, dir := range dirs {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			trimmed = append(trimmed, dir)
+		}
+	}
+
+	return func(o *Option) {
+		if len(trimmed) > 0 {
+			o.provided = true
+			o.sources = append(o.sources, func(*Option, []string) ([]layer, error) {
+				return dropIns(trimmed)
+			})
+		}
+	}
+}
+
+// WithEnvIndexFormat sets the format string used for collection element indexes in environment variable names when
+// environment variable overrides are enabled using WithEnvOverrides. For example, using the format `I%d`,
+// `CONFIG_PEERS_I0` overrides `config.peers.#0`. If the format is not provided, `%d` will be used.
+func WithEnvIndexFormat(format string) func(*Option) {
+	return func(o *Option) {
+		o.envIndexFormat = format
+	}
+}
+
+// WithFilePath sets the file path Option for the configuration. If no file path, search path, or other source is
+// provided, the optional file `application.yaml` in the working directory will be used for loading the configuration.
+//
+// If the Option is provided multiple times, the files are layered in the order provided. See WithFilePaths.
 func WithFilePath(filePath string) func(*Option) {
+	return WithFilePaths(filePath)
+}
+
+// WithFileName sets the name of the configuration file to discover in the search paths provided using WithSearchPaths
+// or WithDefaultSearchPaths. If the file name is not provided, `application.yaml` will be used.
+func WithFileName(fileName string) func(*Option) {
+	return func(o *Option) {
+		o.fileName = strings.TrimSpace(fileName)
+	}
+}
+
+// WithFilePaths adds the provided required file paths to the Option for the configuration. The files are merged in
+// the order provided, with each file taking precedence over the files before it (e.g. base, environment, local
+// overrides):
+//   - scalar values replace the value for the same path, including any sub-paths
+//   - maps are merged recursively, retaining paths that are not present in the overriding file
+//   - collections (e.g. sequences) replace the entire collection for the same path
+//
+// Loading the configuration fails if a required file does not exist. Use WithOptionalFilePaths for files that may
+// not exist.
+func WithFilePaths(filePaths ...string) func(*Option) {
+	return withFiles(true, filePaths)
+}
+
+// WithFS adds the configuration file with the provided path in the provided fs.FS (e.g. embed.FS) to the Option for
+// the configuration. The format of the file is determined by its extension.
+//
+// See WithBytes for the precedence of content provided using WithFS.
+func WithFS(fsys fs.FS, filePath string) func(*Option) {
+	return withSource(newFSSource(fsys, strings.TrimSpace(filePath)), true)
+}
+
+// WithFlagSet adds the flag.FlagSet Option for the configuration. Each flag that was set when the flag.FlagSet was
+// parsed overrides the configuration value for the path matching the flag name (e.g. `--value.duration=5s` for
+// `config.value.duration`), taking precedence over the sources provided before the Option. Flags defined using
+// Overrides override the configuration value for each collected `path=value` pair.
+//
+// The flag.FlagSet must be parsed before the configuration is loaded.
+func WithFlagSet(flagSet *flag.FlagSet) func(*Option) {
+	if flagSet == nil {
+		return func(*Option) {}
+	}
+
+	return withOverlay(func(o *Option) overlay {
+		return newFlagSetSource(flagSet, o)
+	})
+}
+
+// WithOptionalFilePaths adds the provided optional file paths to the Option for the configuration. Optional files
+// that do not exist are skipped, and are otherwise merged in the order provided in the same way as WithFilePaths.
+func WithOptionalFilePaths(filePaths ...string) func(*Option) {
+	return withFiles(false, filePaths)
+}
+
+// WithOptionalSource adds the provided optional Source to the Option for the configuration. The Source is skipped if
+// the error returned when reading it wraps os.ErrNotExist.
+//
+// See WithSource for the precedence of the Source.
+func WithOptionalSource(src Source) func(*Option) {
+	return withSource(src, false)
+}
+
+// WithProfiles sets the active profiles Option for the configuration. For each configuration file, the optional file
+// for each active profile, named `<name>-<profile>.<ext>` (e.g. `application-prod.yaml` for `application.yaml`), is
+// merged after the file in the order the profiles are provided.
+//
+// If the active profiles are not provided, the comma-separated list of profiles defined by the environment variable
+// `CONFIG_PROFILES` will be used.
+func WithProfiles(profiles ...string) func(*Option) {
+	return func(o *Option) {
+		o.profiles = append(o.profiles, splitProfiles(strings.Join(profiles, ","))...)
+	}
+}
+
+// WithReader adds the configuration content read from the provided io.Reader in the provided format (e.g. `yaml`,
+// `.json`) to the Option for the configuration. The content is read once, when the configuration is first loaded.
+//
+// See WithBytes for the precedence of content provided using WithReader.
+func WithReader(r io.Reader, format string) func(*Option) {
+	return withSource(newReaderSource(r, format), true)
+}
+
+// WithRoot sets the root path Option for the configuration. Each top-level key in the configuration file must match
+// the root path, and the root path is prepended to any path that does not already begin with it. If the root path is
+// not provided, `config` will be used.
+func WithRoot(root string) func(*Option) {
+	return func(o *Option) {
+		o.root = strings.TrimSpace(root)
+		o.rootless = false
+	}
+}
+
+// WithSearchMode sets the SearchMode Option used for discovering configuration files in the search paths. If the
+// SearchMode is not provided, SearchFirst will be used.
+func WithSearchMode(mode SearchMode) func(*Option) {
+	return func(o *Option) {
+		o.searchMode = mode
+	}
+}
+
+// WithSearchPaths adds the provided directories, in order of decreasing precedence, to the search paths Option for
+// the configuration. The configuration file (see WithFileName) is discovered in the search paths using the SearchMode
+// (see WithSearchMode), and the discovered files are merged in the position of the first search path Option relative
+// to the other sources (e.g. before the files provided using a subsequent WithFilePath). The discovered files are
+// recorded in the LoadReport returned by Config.Report. Files are discovered again on each reload, and files that
+// appear in a search path are detected by WatchFile.
+func WithSearchPaths(dirs ...string) func(*Option) {
+	var trimmed []string
+	for _, dir := range dirs {
+		if dir = strings.TrimSpace(dir); dir != "" {
+			trimmed = append(trimmed, dir)
+		}
+	}
+	return withSearchPaths(trimmed)
+}
+
+// WithSecretDir adds the secret directory with the provided path (e.g. `/run/secrets` or a Kubernetes secret volume)
+// to the Option for the configuration. Each file in the directory is mapped to the path formed by joining the provided
+// prefix and the file name, resolved relative to the root path of the configuration, and the file content, with any
+// trailing newline removed, is used as the value. For example, the file `password` with the prefix `db` (or
+// `config.db`) is mapped to the path `config.db.password`.
+//
+// Secret values take precedence over the values from the sources provided before the Option, and are used as-is,
+// without interpolating placeholder values. Secret directories that do not exist are skipped.
+func WithSecretDir(dir string, prefix string) func(*Option) {
+	if dir = strings.TrimSpace(dir); dir == "" {
+		return func(*Option) {}
+	}
+	prefix = strings.TrimSpace(prefix)
+	return func(o *Option) {
+		o.provided = true
+		o.sources = append(o.sources, func(o *Option, _ []string) ([]layer, error) {
+			return []layer{{source: newSecretDirSource(dir, prefix, o.namespace()), literal: true}}, nil
+		})
+	}
+}
+
+// WithSource adds the provided Source to the Option for the configuration. The Source takes precedence over the
+// sources provided before the Option (e.g. files provided using WithFilePath), and sources provided after it take
+// precedence over the Source. Loading the configuration fails if the Source cannot be read.
+func WithSource(src Source) func(*Option) {
+	return withSource(src, true)
+}
+
+// WithStrictOverrides sets the Option for returning an error when loading the configuration if a command-line override
+// provided using WithArgs or WithFlagSet does not match an existing configuration path. If the Option is not
+// provided, overrides for unknown paths add the path to the configuration.
+//
+// When used with WithFlagSet, each flag set in the flag.FlagSet must match a configuration path, so a dedicated
+// flag.FlagSet should be used.
+func WithStrictOverrides() func(*Option) {
+	return func(o *Option) {
+		o.strict = true
+	}
+}
+
+// WithoutRoot sets the configuration as rootless, allowing the configuration file to define any number of top-level
+// keys (e.g. `server` and `logging`). Paths are resolved as provided.
+func WithoutRoot() func(*Option) {
+	return func(o *Option) {
+		o.root = ""
+		o.rootless = true
+	}
+}
+
+// namespace returns the root Path for the configuration, which is empty if the configuration is rootless.
+func (o *Option) namespace() Path {
+	if o.rootless {
+		return ""
+	}
+
+	if o.root != "" {
+		return Path(o.root)
+	}
+	return defaultRoot
+}
+
+// configFile is a container for a configuration file path and whether the file is required.
+type configFile struct {
+	path     string
+	required bool
+}
+
+func withFiles(required bool, filePaths []string) func(*Option) {
+	var files []configFile
+	for _, filePath := range filePaths {
+		if filePath = strings.TrimSpace(filePath); filePath != "" {
+			files = append(files, configFile{path: filePath, required: required})
+		}
+	}
+
+	return func(o *Option) {
+		if len(files) > 0 {
+			o.provided = true
+			o.sources = append(o.sources, func(_ *Option, profiles []string) ([]layer, error) {
+				return fileLayers(files, profiles), nil
+			})
+		}
+	}
+}
+
+func withSearchPaths(dirs []string) func(*Option) {
+	return func(o *Option) {
+		if len(dirs) == 0 {
+			return
+		}
+
+		o.provided = true
+		if len(o.searchPaths) == 0 {
+			o.sources = append(o.sources, func(o *Option, profiles []string) ([]layer, error) {
+				return fileLayers(o.discover(), profiles), nil
+			})
+		}
+		o.searchPaths = append(o.searchPaths, dirs...)
+	}
+}
+
+func withOverlay(newOverlay func(o *Option) overlay) func(*Option) {
+	return func(o *Option) {
+		o.sources = append(o.sources, func(o *Option, _ []string) ([]layer, error) {
+			return []layer{{source: newOverlay(o), required: true}}, nil
+		})
+	}
+}
+
+func withSource(src Source, required bool) func(*Option) {
 	return func(o *Option) {
-		o.filePath = strings.TrimSpace(filePath)
+		if src != nil {
+			o.provided = true
+			o.sources = append(o.sources, func(*Option, []string) ([]layer, error) {
+				return []layer{{source: src, required: required}}, nil
+			})
+		}
 	}
 }
@@ -52,6 +52,16 @@ func (p Path) MarshalText() ([]byte, error) {
 	return []byte(p.String()), nil
 }
 
+// Parent returns the Path without its last element. For example, if Path.String returns `foo.bar.gopher`, then Parent
+// would return `foo.bar`. The Parent of a Path with a single element is empty.
+func (p Path) Parent() Path {
+	s := p.String()
+	if idx := strings.LastIndex(s, "."); idx != -1 {
+		return Path(s[:idx])
+	}
+	return ""
+}
+
 // String returns the raw string value for the Configuration Path.
 func (p Path) String() string {
 	return strings.TrimSpace(string(p))
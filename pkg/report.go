@@ -0,0 +1,50 @@
+package config
+
+// Enumeration of the outcomes of reading a configuration source.
+const (
+	SourceFound   = SourceStatus("found")
+	SourceSkipped = SourceStatus("skipped")
+	SourceFailed  = SourceStatus("failed")
+)
+
+// SourceStatus defines the type for the outcome of reading a configuration source.
+type SourceStatus string
+
+// SourceReport records the outcome of reading a configuration source (e.g. file).
+type SourceReport struct {
+	Err      error
+	Name     string
+	Required bool
+	Status   SourceStatus
+}
+
+// LoadReport records the outcome of reading each configuration source, in order of increasing precedence.
+type LoadReport struct {
+	Sources []SourceReport
+}
+
+// Failed returns whether any configuration source could not be read.
+func (r LoadReport) Failed() bool {
+	for _, s := range r.Sources {
+		if s.Status == SourceFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// Report returns the LoadReport for the configuration sources read when the configuration was last loaded.
+func (c *Config) Report() LoadReport {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.report
+}
+
+// Report returns the LoadReport for the configuration sources read when the default configuration was last loaded.
+func Report() (LoadReport, error) {
+	c, err := instance()
+	if err != nil {
+		return LoadReport{}, err
+	}
+	return c.Report(), nil
+}
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Source represents a layer of configuration values, such as a configuration file or a remote configuration store.
+//
+// Sources are read in the order the options providing them were provided each time the configuration is loaded or
+// reloaded, and the values read from each Source are merged onto the values read from the sources before it.
+// Default values provided using WithDefaults and environment variable and command-line overrides are layered in the
+// same order, so overrides only take precedence over the sources provided before them. Default values registered
+// using SetDefault are always merged first.
+type Source interface {
+	// Name returns the name used for identifying the Source in the LoadReport (e.g. the file path).
+	Name() string
+
+	// Read reads the configuration values for the Source as a nested map, using maps for sub-paths and slices for
+	// collections. If the Source is optional and does not exist, the returned error should wrap os.ErrNotExist.
+	Read() (map[string]any, error)
+}
+
+// NewSource creates a new Source with the provided name that uses the provided function for reading the configuration
+// values.
+func NewSource(name string, read func() (map[string]any, error)) Source {
+	return source{name: strings.TrimSpace(name), read: read}
+}
+
+// source is the implementation of Source for configuration values read using a function.
+type source struct {
+	name string
+	read func() (map[string]any, error)
+}
+
+// Name returns the name of the source.
+func (s source) Name() string {
+	return s.name
+}
+
+// Read reads the configuration values for the source.
+func (s source) Read() (map[string]any, error) {
+	if s.read == nil {
+		return nil, fmt.Errorf("configuration: no reader provided for source %s", s.name)
+	}
+	return s.read()
+}
+
+// layer is a container for a Source, whether loading the configuration fails if the Source cannot be read, and
+// whether the values read from the Source are used as-is, without interpolating placeholder values.
+type layer struct {
+	source   Source
+	required bool
+	literal  bool
+}
+
+// overlay is implemented by sources that override the values for the paths defined by the sources before them (e.g.
+// environment variables), rather than providing values that are merged.
+type overlay interface {
+	Source
+
+	// override replaces the values in the provided mapping, which contains the values merged from the sources before
+	// the overlay.
+	override(mapping configMap) error
+}
+
+// layerFunc returns the layers for a configuration source Option, using the provided active profiles.
+type layerFunc func(o *Option, profiles []string) ([]layer, error)
+
+// layers returns the layers for the configuration sources, in the order the options were provided.
+func (o *Option) layers(profiles []string) ([]layer, error) {
+	var layers []layer
+	for _, fn := range o.sources {
+		l, err := fn(o, profiles)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, l...)
+	}
+	return layers, nil
+}
+
+// fileLayers returns the layers for the provided files, each followed by the optional file for each of the provided
+// profiles.
+func fileLayers(files []configFile, profiles []string) []layer {
+	files = withProfiles(files, profiles)
+	layers := make([]layer, len(files))
+	for i, f := range files {
+		layers[i] = layer{source: fileSource(f.path), required: f.required}
+	}
+	return layers
+}
+
+// layerFiles returns the file paths for the layers read from configuration files.
+func layerFiles(layers []layer) []string {
+	var filePaths []string
+	for _, l := range layers {
+		if f, ok := l.source.(fileSource); ok {
+			filePaths = append(filePaths, string(f))
+		}
+	}
+	return filePaths
+}
+
+// newBytesSource creates a new source for the provided content in the provided format.
+func newBytesSource(b []byte, format string) source {
+	return source{
+		name: "bytes",
+		read: func() (map[string]any, error) {
+			return decodeConfig(b, format)
+		},
+	}
+}
+
+// fileSource is the implementation of Source for the configuration file with the file path.
+type fileSource string
+
+// Name returns the file path of the fileSource.
+func (f fileSource) Name() string {
+	return string(f)
+}
+
+// Read reads the configuration values from the configuration file.
+func (f fileSource) Read() (map[string]any, error) {
+	return readConfig(string(f))
+}
+
+// newFSSource creates a new source for the configuration file with the provided path in the provided fs.FS.
+func newFSSource(fsys fs.FS, filePath string) source {
+	return source{
+		name: "fs:" + filePath,
+		read: func() (map[string]any, error) {
+			return readConfigFrom(filePath, func(name string) ([]byte, error) {
+				return fs.ReadFile(fsys, name)
+			})
+		},
+	}
+}
+
+// newReaderSource creates a new source for the content read from the provided io.Reader in the provided format. The
+// content is read once and retained for subsequent reads.
+func newReaderSource(r io.Reader, format string) source {
+	var (
+		b    []byte
+		err  error
+		once sync.Once
+	)
+	return source{
+		name: "reader",
+		read: func() (map[string]any, error) {
+			once.Do(func() {
+				b, err = io.ReadAll(r)
+			})
+
+			if err != nil {
+				return nil, fmt.Errorf("configuration: %w", err)
+			}
+			return decodeConfig(b, format)
+		},
+	}
+}
+
+// newSecretDirSource creates a new source for the secret files in the provided directory (e.g. `/run/secrets` or a
+// Kubernetes secret volume). Each file name is mapped to a path under the provided prefix, resolved relative to the
+// provided root Path, and the file content, with any trailing newline removed, is mapped to the value for the path.
+// Hidden files (e.g. the Kubernetes `..data` link) and sub-directories are skipped.
+func newSecretDirSource(dir string, prefix string, root Path) source {
+	return source{
+		name: "secrets:" + dir,
+		read: func() (map[string]any, error) {
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				return nil, fmt.Errorf("configuration: %w", err)
+			}
+
+			rawConfig := make(map[string]any)
+			for _, e := range entries {
+				if strings.HasPrefix(e.Name(), ".") {
+					continue
+				}
+
+				filePath := filepath.Join(dir, e.Name())
+				info, err := os.Stat(filePath)
+				if err != nil {
+					return nil, fmt.Errorf("configuration: %w", err)
+				}
+
+				if info.IsDir() {
+					continue
+				}
+
+				b, err := os.ReadFile(filePath)
+				if err != nil {
+					return nil, fmt.Errorf("configuration: %w", err)
+				}
+
+				value := strings.TrimSuffix(strings.TrimSuffix(string(b), "\n"), "\r")
+				if err := setNested(rawConfig, resolve(root, Path(prefix).Join(Path(e.Name()))), value); err != nil {
+					return nil, err
+				}
+			}
+			return rawConfig, nil
+		},
+	}
+}
+
+// setNested sets the provided value for the provided path in the provided nested map, creating the intermediate maps
+// for the path as needed.
+func setNested(m map[string]any, path Path, value any) error {
+	elements := strings.Split(path.String(), ".")
+	for _, e := range elements[:len(elements)-1] {
+		switch v := m[e].(type) {
+		case nil:
+			child := make(map[string]any)
+			m[e] = child
+			m = child
+		case map[string]any:
+			m = v
+		default:
+			return fmt.Errorf("configuration: path %s conflicts with the value for %s", path, e)
+		}
+	}
+
+	key := elements[len(elements)-1]
+	if _, ok := m[key].(map[string]any); ok {
+		return fmt.Errorf("configuration: path %s conflicts with its sub-paths", path)
+	}
+	m[key] = value
+	return nil
+}
+
+// decodeConfig decodes the provided content using the FormatDecoder registered for the provided format.
+func decodeConfig(b []byte, ext string) (map[string]any, error) {
+	decoder, ok := format(ext)
+	if !ok {
+		return nil, fmt.Errorf("configuration: unsupported format, expected one of %s, but found %s",
+			formatExtensions(), ext)
+	}
+	return decoder(b)
+}
+
+func readConfig(filePath string) (map[string]any, error) {
+	return readConfigFrom(filePath, os.ReadFile)
+}
+
+func readConfigFrom(filePath string, readFile func(string) ([]byte, error)) (map[string]any, error) {
+	fileExtension := regexp.MustCompile(fileExtensionPattern).FindString(filePath)
+	decoder, ok := format(fileExtension)
+	if !ok {
+		return nil, fmt.Errorf(
+			"configuration: unsupported file type, expected one of %s, but found %s for path %s",
+			formatExtensions(), fileExtension, filePath)
+	}
+	return readConfigAndThen(filePath, readFile, decoder)
+}
+
+func readConfigAndThen(filePath string, readFile func(string) ([]byte, error), decoder FormatDecoder) (map[string]any, error) {
+	if strings.TrimSpace(filePath) == "" {
+		return nil, errors.New("configuration: file path cannot be empty")
+	}
+
+	bytes, err := readFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("configuration: %w", err)
+	}
+	return decoder(bytes)
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Change represents a configuration value that was added, removed, or changed for a Path.
+type Change struct {
+	Path Path
+	Old  string
+	New  string
+}
+
+// Diff represents the set of changes between two versions of the configuration mapping.
+type Diff struct {
+	Added   []Change
+	Removed []Change
+	Changed []Change
+}
+
+// newDiff computes the Diff between the provided old and new configuration mappings. Changes are sorted by Path.
+func newDiff(old configMap, new configMap) Diff {
+	var diff Diff
+	for p, v := range new {
+		if o, ok := old[p]; !ok {
+			diff.Added = append(diff.Added, Change{Path: p, New: v})
+		} else if o != v {
+			diff.Changed = append(diff.Changed, Change{Path: p, Old: o, New: v})
+		}
+	}
+
+	for p, o := range old {
+		if _, ok := new[p]; !ok {
+			diff.Removed = append(diff.Removed, Change{Path: p, Old: o})
+		}
+	}
+
+	for _, changes := range [][]Change{diff.Added, diff.Removed, diff.Changed} {
+		slices.SortFunc(changes, func(a, b Change) int {
+			return strings.Compare(a.Path.String(), b.Path.String())
+		})
+	}
+	return diff
+}
+
+// Empty returns whether the Diff does not contain any changes.
+func (d Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// under returns the subset of the Diff for the provided Path and any of its sub-paths.
+func (d Diff) under(path Path) Diff {
+	filter := func(changes []Change) []Change {
+		var result []Change
+		for _, c := range changes {
+			if c.Path.Equals(path) || strings.HasPrefix(strings.ToLower(c.Path.String()), strings.ToLower(path.String())+".") {
+				result = append(result, c)
+			}
+		}
+		return result
+	}
+	return Diff{Added: filter(d.Added), Removed: filter(d.Removed), Changed: filter(d.Changed)}
+}
+
+// subscription is a container for a function that is notified of changes to a Path and its sub-paths.
+type subscription struct {
+	path Path
+	fn   func(Diff)
+}
+
+// Watch registers a function that is called with the Diff of changed values whenever Reload or Set changes the value
+// for the provided path, or any of its sub-paths.
+//
+// The returned function cancels the subscription.
+func (c *Config) Watch(path string, fn func(Diff)) func() {
+	return c.subscribe(c.resolve(Path(path)), fn)
+}
+
+// subscribe registers the function that is notified of changes to the provided resolved path.
+func (s *store) subscribe(path Path, fn func(Diff)) func() {
+	s.subscriptionMutex.Lock()
+	defer s.subscriptionMutex.Unlock()
+
+	if s.subscriptions == nil {
+		s.subscriptions = make(map[int]*subscription)
+	}
+
+	s.subscriptionID++
+	id := s.subscriptionID
+	s.subscriptions[id] = &subscription{path: path, fn: fn}
+	return func() {
+		s.subscriptionMutex.Lock()
+		defer s.subscriptionMutex.Unlock()
+		delete(s.subscriptions, id)
+	}
+}
+
+// notify calls the functions for each subscription with the subset of the Diff relevant to the subscription path.
+func (s *store) notify(diff Diff) {
+	if diff.Empty() {
+		return
+	}
+
+	s.subscriptionMutex.Lock()
+	subscriptions := make([]*subscription, 0, len(s.subscriptions))
+	for _, id := range slices.Sorted(maps.Keys(s.subscriptions)) {
+		subscriptions = append(subscriptions, s.subscriptions[id])
+	}
+	s.subscriptionMutex.Unlock()
+
+	for _, sub := range subscriptions {
+		if d := diff.under(sub.path); !d.Empty() {
+			sub.fn(d)
+		}
+	}
+}
+
+// Watch registers a function that is called with the Diff of changed values whenever Reload or Set changes the value
+// for the provided path, or any of its sub-paths, in the default configuration.
+//
+// The returned function cancels the subscription.
+func Watch(path string, fn func(Diff)) (func(), error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Watch(path, fn), nil
+}
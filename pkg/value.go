@@ -1,17 +1,54 @@
 package config
 
-import "fmt"
-
 // Value retrieves the value for the provided path.
 //
+// The returned error will be non-nil if the value corresponding to the provided path could not be found.
+func (c *Config) Value(path string) (string, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.value(Path(path))
+}
+
+// ValueMustResolve is similar behavior to Value, but panics if an error occurs.
+func (c *Config) ValueMustResolve(path string) string {
+	v, err := c.Value(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Values retrieves the collection of values (e.g. YAML sequence) for the provided path.
+//
+// The returned error will be non-nil if the value corresponding to the provided path:
+//   - could not be found
+//   - was found, but does not represent a collection
+func (c *Config) Values(path string) ([]string, error) {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	return c.values(Path(path))
+}
+
+// ValuesMustResolve is similar behavior to Values, but panics if an error occurs.
+func (c *Config) ValuesMustResolve(path string) []string {
+	v, err := c.Values(path)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// Value retrieves the value for the provided path from the default configuration.
+//
 // The returned error will be non-nil if:
 //   - the configuration has not been initialized
 //   - the value corresponding to the provided path could not be found
 func Value(path string) (string, error) {
-	if config == nil {
-		return "", fmt.Errorf("configuration: %w", ErrNotInitialized)
+	c, err := instance()
+	if err != nil {
+		return "", err
 	}
-	return config.value(Path(path))
+	return c.Value(path)
 }
 
 // ValueMustResolve is similar behavior to Value, but panics if an error occurs.
@@ -23,11 +60,24 @@ func ValueMustResolve(path string) string {
 	return v
 }
 
-// ValuesMustResolve is similar behavior to values, but panics if an error occurs.
+// Values retrieves the collection of values (e.g. YAML sequence) for the provided path from the default configuration.
+//
+// The returned error will be non-nil if:
+//   - the configuration has not been initialized
+//   - the value corresponding to the provided path could not be found or does not represent a collection
+func Values(path string) ([]string, error) {
+	c, err := instance()
+	if err != nil {
+		return nil, err
+	}
+	return c.Values(path)
+}
+
+// ValuesMustResolve is similar behavior to Values, but panics if an error occurs.
 func ValuesMustResolve(path string) []string {
-	v, err := config.values(Path(path))
+	v, err := Values(path)
 	if err != nil {
 		panic(err)
 	}
 	return v
-}
\ No newline at end of file
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"maps"
+	"os"
+	"slices"
+	"time"
+)
+
+const (
+	// The default interval for polling the configuration file for modifications.
+	defaultWatchInterval = time.Second
+
+	// The default duration a modified configuration file must remain unchanged before the configuration is reloaded.
+	defaultWatchDebounce = 500 * time.Millisecond
+)
+
+// WatchOption is a container for optional properties that can be used for watching the configuration file.
+type WatchOption struct {
+	debounce time.Duration
+	interval time.Duration
+	onError  func(error)
+}
+
+// WithWatchInterval sets the interval used for polling the configuration file for modifications. If the interval is
+// not provided or is not positive, the configuration file will be polled every second.
+func WithWatchInterval(interval time.Duration) func(*WatchOption) {
+	return func(o *WatchOption) {
+		if interval > 0 {
+			o.interval = interval
+		}
+	}
+}
+
+// WithWatchDebounce sets the duration a modified configuration file must remain unchanged before the configuration is
+// reloaded. If the duration is not provided or is negative, a default of 500ms will be used.
+func WithWatchDebounce(debounce time.Duration) func(*WatchOption) {
+	return func(o *WatchOption) {
+		if debounce >= 0 {
+			o.debounce = debounce
+		}
+	}
+}
+
+// WithWatchErrorHandler sets the function that will be called if reloading the configuration fails. The current
+// configuration is retained if a reload fails. If the function is nil, errors are ignored.
+func WithWatchErrorHandler(onError func(error)) func(*WatchOption) {
+	return func(o *WatchOption) {
+		if onError != nil {
+			o.onError = onError
+		}
+	}
+}
+
+// fileState records the modification state of a configuration file.
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// WatchFile polls the configuration files for modifications using the provided optional properties, and reloads the
+// configuration once a detected modification has settled for the debounce duration. Files added to drop-in
+// directories or search paths are detected as modifications, as are removed files.
+//
+// WatchFile blocks until the provided context is done.
+func (c *Config) WatchFile(ctx context.Context, options ...func(*WatchOption)) {
+	opts := &WatchOption{
+		debounce: defaultWatchDebounce,
+		interval: defaultWatchInterval,
+		onError:  func(error) {},
+	}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	ticker := time.NewTicker(opts.interval)
+	defer ticker.Stop()
+
+	var modified time.Time
+	states := c.fileStates()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s := c.fileStates(); !maps.Equal(s, states) {
+				states = s
+				modified = time.Now()
+				continue
+			}
+
+			if !modified.IsZero() && time.Since(modified) >= opts.debounce {
+				modified = time.Time{}
+				if err := c.Reload(); err != nil {
+					opts.onError(err)
+				}
+				states = c.fileStates()
+			}
+		}
+	}
+}
+
+// fileStates returns the current modification state for each configuration file read by the last load, and for each
+// configuration file a reload would read (e.g. a new drop-in fragment or a file appearing in a search path).
+func (s *store) fileStates() map[string]fileState {
+	s.mutex.RLock()
+	filePaths := slices.Clone(s.filePaths)
+	s.mutex.RUnlock()
+
+	if layers, err := s.options.layers(s.profiles()); err == nil {
+		filePaths = append(filePaths, layerFiles(layers)...)
+	}
+
+	states := make(map[string]fileState, len(filePaths))
+	for _, filePath := range filePaths {
+		var state fileState
+		if info, err := os.Stat(filePath); err == nil {
+			state.exists = true
+			state.modTime = info.ModTime()
+			state.size = info.Size()
+			if b, err := os.ReadFile(filePath); err == nil {
+				state.sum = sha256.Sum256(b)
+			}
+		}
+		states[filePath] = state
+	}
+	return states
+}
+
+// WatchFile polls the default configuration files for modifications using the provided optional properties, and
+// reloads the default configuration once a detected modification has settled for the debounce duration.
+//
+// WatchFile blocks until the provided context is done, and returns a non-nil error if the default configuration has
+// not been initialized.
+func WatchFile(ctx context.Context, options ...func(*WatchOption)) error {
+	c, err := instance()
+	if err != nil {
+		return err
+	}
+	c.WatchFile(ctx, options...)
+	return nil
+}